@@ -0,0 +1,47 @@
+package apikey
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	ScopeReadCampaigns    = "read:campaigns"
+	ScopeReadTransactions = "read:transactions"
+	ScopeWriteCampaigns   = "write:campaigns"
+)
+
+type APIKey struct {
+	ID         int
+	UserID     int
+	Name       string
+	Prefix     string
+	KeyHash    string
+	Scopes     string
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (k APIKey) ScopeList() []string {
+	if k.Scopes == "" {
+		return []string{}
+	}
+
+	return strings.Split(k.Scopes, ",")
+}
+
+func (k APIKey) HasScope(scope string) bool {
+	for _, s := range k.ScopeList() {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (k APIKey) IsExpired() bool {
+	return k.ExpiresAt != nil && k.ExpiresAt.Before(time.Now())
+}
@@ -0,0 +1,52 @@
+package apikey
+
+import "time"
+
+type APIKeyFormatter struct {
+	ID         int        `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreatedAPIKeyFormatter hanya dipakai saat key dibuat karena memuat key plain text
+type CreatedAPIKeyFormatter struct {
+	APIKeyFormatter
+	Key string `json:"key"`
+}
+
+func FormatAPIKey(apiKey APIKey) APIKeyFormatter {
+	formatter := APIKeyFormatter{
+		ID:         apiKey.ID,
+		Name:       apiKey.Name,
+		Prefix:     keyPrefix + "_" + apiKey.Prefix,
+		Scopes:     apiKey.ScopeList(),
+		ExpiresAt:  apiKey.ExpiresAt,
+		LastUsedAt: apiKey.LastUsedAt,
+		CreatedAt:  apiKey.CreatedAt,
+	}
+
+	return formatter
+}
+
+func FormatAPIKeys(apiKeys []APIKey) []APIKeyFormatter {
+	apiKeysFormatter := []APIKeyFormatter{}
+
+	for _, apiKey := range apiKeys {
+		apiKeysFormatter = append(apiKeysFormatter, FormatAPIKey(apiKey))
+	}
+
+	return apiKeysFormatter
+}
+
+func FormatCreatedAPIKey(apiKey APIKey, rawKey string) CreatedAPIKeyFormatter {
+	formatter := CreatedAPIKeyFormatter{
+		APIKeyFormatter: FormatAPIKey(apiKey),
+		Key:             rawKey,
+	}
+
+	return formatter
+}
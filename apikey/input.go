@@ -0,0 +1,15 @@
+package apikey
+
+import "auth-gorm-echo/user"
+
+type CreateAPIKeyInput struct {
+	Name          string   `json:"name" validate:"required,max=100"`
+	Scopes        []string `json:"scopes" validate:"required,min=1,dive,oneof=read:campaigns read:transactions write:campaigns"`
+	ExpiresInDays int      `json:"expires_in_days" validate:"omitempty,min=1,max=365"`
+	User          user.User
+}
+
+type DeleteAPIKeyInput struct {
+	ID   int `param:"id" validate:"required"`
+	User user.User
+}
@@ -0,0 +1,89 @@
+package apikey
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	Save(ctx context.Context, apiKey APIKey) (APIKey, error)
+	FindByPrefix(ctx context.Context, prefix string) (APIKey, error)
+	FindByUserID(ctx context.Context, userID int) ([]APIKey, error)
+	Delete(ctx context.Context, ID int, userID int) (bool, error)
+	UpdateLastUsed(ctx context.Context, ID int, lastUsedAt time.Time) error
+}
+
+type repository struct {
+	db      *gorm.DB
+	timeout time.Duration
+}
+
+func NewRepository(db *gorm.DB, timeout time.Duration) *repository {
+	return &repository{db, timeout}
+}
+
+func (r *repository) withTimeout(ctx context.Context) (*gorm.DB, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	return r.db.WithContext(ctx), cancel
+}
+
+func (r *repository) Save(ctx context.Context, apiKey APIKey) (APIKey, error) {
+	db, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	err := db.Create(&apiKey).Error
+	if err != nil {
+		return apiKey, err
+	}
+
+	return apiKey, nil
+}
+
+func (r *repository) FindByPrefix(ctx context.Context, prefix string) (APIKey, error) {
+	db, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	var apiKey APIKey
+
+	err := db.Where("prefix = ?", prefix).Find(&apiKey).Error
+	if err != nil {
+		return apiKey, err
+	}
+
+	return apiKey, nil
+}
+
+func (r *repository) FindByUserID(ctx context.Context, userID int) ([]APIKey, error) {
+	db, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	var apiKeys []APIKey
+
+	err := db.Where("user_id = ?", userID).Order("created_at desc").Find(&apiKeys).Error
+	if err != nil {
+		return apiKeys, err
+	}
+
+	return apiKeys, nil
+}
+
+func (r *repository) Delete(ctx context.Context, ID int, userID int) (bool, error) {
+	db, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	result := db.Where("id = ? AND user_id = ?", ID, userID).Delete(&APIKey{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+func (r *repository) UpdateLastUsed(ctx context.Context, ID int, lastUsedAt time.Time) error {
+	db, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	return db.Model(&APIKey{}).Where("id = ?", ID).UpdateColumn("last_used_at", lastUsedAt).Error
+}
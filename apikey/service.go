@@ -0,0 +1,163 @@
+package apikey
+
+import (
+	"auth-gorm-echo/apperror"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// format key: cf_<prefix>_<secret>, prefix disimpan plain supaya key bisa
+// dicari dan dikenali user, secret hanya disimpan hash-nya
+const keyPrefix = "cf"
+
+// last_used_at cukup diupdate sekali per menit
+const lastUsedResolution = time.Minute
+
+var (
+	ErrInvalidAPIKey  = errors.New("Invalid API key")
+	ErrExpiredAPIKey  = errors.New("API key has expired")
+	ErrAPIKeyNotFound = apperror.NotFound("api_key.not_found", "API key not found")
+)
+
+type Service interface {
+	CreateAPIKey(ctx context.Context, input CreateAPIKeyInput) (APIKey, string, error)
+	GetAPIKeys(ctx context.Context, userID int) ([]APIKey, error)
+	DeleteAPIKey(ctx context.Context, input DeleteAPIKeyInput) error
+	Authenticate(ctx context.Context, rawKey string) (APIKey, error)
+}
+
+type service struct {
+	repository Repository
+}
+
+func NewService(repository Repository) *service {
+	return &service{repository}
+}
+
+// CreateAPIKey mengembalikan key dalam bentuk plain text, key ini hanya
+// ditampilkan sekali
+func (s *service) CreateAPIKey(ctx context.Context, input CreateAPIKeyInput) (APIKey, string, error) {
+	apiKey := APIKey{}
+
+	prefix, err := randomString(5)
+	if err != nil {
+		return apiKey, "", err
+	}
+
+	secret, err := randomString(20)
+	if err != nil {
+		return apiKey, "", err
+	}
+
+	apiKey.UserID = input.User.ID
+	apiKey.Name = input.Name
+	apiKey.Prefix = prefix
+	apiKey.KeyHash = hashSecret(secret)
+	apiKey.Scopes = strings.Join(uniqueScopes(input.Scopes), ",")
+
+	if input.ExpiresInDays > 0 {
+		expiresAt := time.Now().AddDate(0, 0, input.ExpiresInDays)
+		apiKey.ExpiresAt = &expiresAt
+	}
+
+	newAPIKey, err := s.repository.Save(ctx, apiKey)
+	if err != nil {
+		return newAPIKey, "", err
+	}
+
+	rawKey := fmt.Sprintf("%s_%s_%s", keyPrefix, prefix, secret)
+
+	return newAPIKey, rawKey, nil
+}
+
+func (s *service) GetAPIKeys(ctx context.Context, userID int) ([]APIKey, error) {
+	apiKeys, err := s.repository.FindByUserID(ctx, userID)
+	if err != nil {
+		return apiKeys, err
+	}
+
+	return apiKeys, nil
+}
+
+func (s *service) DeleteAPIKey(ctx context.Context, input DeleteAPIKeyInput) error {
+	deleted, err := s.repository.Delete(ctx, input.ID, input.User.ID)
+	if err != nil {
+		return err
+	}
+
+	if !deleted {
+		return ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+func (s *service) Authenticate(ctx context.Context, rawKey string) (APIKey, error) {
+	parts := strings.Split(rawKey, "_")
+	if len(parts) != 3 || parts[0] != keyPrefix {
+		return APIKey{}, ErrInvalidAPIKey
+	}
+
+	apiKey, err := s.repository.FindByPrefix(ctx, parts[1])
+	if err != nil {
+		return APIKey{}, err
+	}
+
+	if apiKey.ID == 0 || subtle.ConstantTimeCompare([]byte(apiKey.KeyHash), []byte(hashSecret(parts[2]))) != 1 {
+		return APIKey{}, ErrInvalidAPIKey
+	}
+
+	if apiKey.IsExpired() {
+		return APIKey{}, ErrExpiredAPIKey
+	}
+
+	now := time.Now()
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) > lastUsedResolution {
+		err = s.repository.UpdateLastUsed(ctx, apiKey.ID, now)
+		if err != nil {
+			return APIKey{}, err
+		}
+
+		apiKey.LastUsedAt = &now
+	}
+
+	return apiKey, nil
+}
+
+func randomString(size int) (string, error) {
+	random := make([]byte, size)
+	_, err := rand.Read(random)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(random)), nil
+}
+
+func hashSecret(secret string) string {
+	hash := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(hash[:])
+}
+
+func uniqueScopes(scopes []string) []string {
+	seen := map[string]bool{}
+	result := []string{}
+
+	for _, scope := range scopes {
+		if !seen[scope] {
+			seen[scope] = true
+			result = append(result, scope)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"auth-gorm-echo/apikey"
+	"auth-gorm-echo/auth"
+	"auth-gorm-echo/campaign"
+	"auth-gorm-echo/config"
+	"auth-gorm-echo/encryption"
+	"auth-gorm-echo/jobs"
+	"auth-gorm-echo/logging"
+	"auth-gorm-echo/mailer"
+	"auth-gorm-echo/metrics"
+	"auth-gorm-echo/pledge"
+	"auth-gorm-echo/storage"
+	"auth-gorm-echo/tracing"
+	"auth-gorm-echo/upload"
+	"auth-gorm-echo/user"
+	"auth-gorm-echo/webhook"
+	"context"
+	"database/sql"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// app berisi semua dependency yang dipakai server maupun subcommand lain
+type app struct {
+	logger                     *slog.Logger
+	db                         *gorm.DB
+	sqlDB                      *sql.DB
+	rdb                        *redis.Client
+	queue                      *jobs.Queue
+	store                      storage.Store
+	userService                user.Service
+	campaignService            campaign.Service
+	progressHub                *campaign.ProgressHub
+	pledgeService              pledge.Service
+	authService                auth.Service
+	apiKeyService              apikey.Service
+	webhookService             webhook.Service
+	avatarUploadService        upload.Service
+	campaignImageUploadService upload.Service
+	// dijalankan saat shutdown sebelum koneksi database dan redis ditutup
+	shutdownHooks []func(context.Context) error
+}
+
+func newApp() *app {
+	logger := logging.New()
+	slog.SetDefault(logger)
+
+	// Connect to database
+	config.DatabaseInit()
+	db := config.GetDB()
+
+	dbGorm, err := db.DB()
+	if err != nil {
+		panic(err)
+	}
+
+	err = dbGorm.Ping()
+	if err != nil {
+		logger.Error("failed to connect to database", "error", err)
+		os.Exit(1)
+	}
+
+	shutdownTracing, err := tracing.Init(context.Background(), "crowdfunding")
+	if err != nil {
+		logger.Error("failed to initialize tracing", "error", err)
+		os.Exit(1)
+	}
+
+	err = db.Use(metrics.GormPlugin{})
+	if err != nil {
+		panic(err)
+	}
+
+	err = db.Use(tracing.GormPlugin{})
+	if err != nil {
+		panic(err)
+	}
+
+	// initialize redis
+	config.RedisInit()
+	config.RedisConnect().AddHook(metrics.RedisHook)
+	config.RedisConnect().AddHook(tracing.RedisHook)
+
+	queryTimeout := config.GetenvDuration("DB_QUERY_TIMEOUT", time.Second*5)
+
+	userRepository := user.NewRepository(db, queryTimeout)
+	campaignRepository := campaign.NewCachedRepository(campaign.NewRepository(db, queryTimeout), userRepository, config.RedisConnect(), campaign.CacheConfig{
+		ListTTL:   config.GetenvDuration("CAMPAIGN_CACHE_LIST_TTL", time.Second*30),
+		DetailTTL: config.GetenvDuration("CAMPAIGN_CACHE_DETAIL_TTL", time.Minute*5),
+	}, logger)
+	apiKeyRepository := apikey.NewRepository(db, queryTimeout)
+
+	loginGuard := user.NewLoginGuard(config.RedisConnect(), user.LoginGuardConfig{
+		BackoffAfter:  config.GetenvInt("LOGIN_BACKOFF_AFTER", 3),
+		BaseDelay:     config.GetenvDuration("LOGIN_BACKOFF_BASE", time.Second),
+		MaxDelay:      config.GetenvDuration("LOGIN_BACKOFF_MAX", time.Minute*5),
+		LockThreshold: config.GetenvInt("LOGIN_LOCK_THRESHOLD", 10),
+		LockDuration:  config.GetenvDuration("LOGIN_LOCK_DURATION", time.Minute*15),
+		IPThreshold:   config.GetenvInt("LOGIN_IP_THRESHOLD", 50),
+		Window:        config.GetenvDuration("LOGIN_FAILURE_WINDOW", time.Hour),
+	})
+
+	// antrian job background, dijalankan oleh worker (lihat worker.go)
+	queue := jobs.NewQueue(config.RedisConnect(), jobs.QueueConfig{
+		VisibilityTimeout: config.GetenvDuration("JOB_VISIBILITY_TIMEOUT", time.Minute*5),
+		MaxAttempts:       config.GetenvInt("JOB_MAX_ATTEMPTS", 8),
+		DeadRetention:     config.GetenvDuration("JOB_DEAD_RETENTION", time.Hour*24*7),
+	})
+
+	// APP_KEY mengenkripsi data sensitif di database (secret TOTP dan webhook)
+	appKey, err := config.AppKey()
+	if err != nil {
+		logger.Error("invalid app key", "error", err)
+		os.Exit(1)
+	}
+
+	cipher, err := encryption.NewCipher(appKey)
+	if err != nil {
+		panic(err)
+	}
+
+	userService := user.NewService(userRepository, loginGuard, cipher, mailer.NewQueueMailer(queue), logger)
+	campaignService := campaign.NewService(campaignRepository, logger)
+	progressHub := campaign.NewProgressHub(config.RedisConnect(), logger)
+	pledgeService := pledge.NewService(pledge.NewRepository(db, queryTimeout), campaignRepository, campaignRepository, logger)
+	authService := auth.NewService()
+	apiKeyService := apikey.NewService(apiKeyRepository)
+	webhookService := webhook.NewService(webhook.NewRepository(db, queryTimeout), queue, webhook.NewSender(webhook.SenderConfig{
+		Timeout:      config.GetenvDuration("WEBHOOK_TIMEOUT", time.Second*10),
+		AllowPrivate: config.Getenv("WEBHOOK_ALLOW_PRIVATE", "false") == "true",
+	}), cipher, logger)
+
+	// storage untuk file upload (lokal atau S3/MinIO)
+	store, err := storage.NewStore()
+	if err != nil {
+		panic(err)
+	}
+	storage.SetDefault(store)
+
+	avatarUploadService := upload.NewService(store, upload.Config{
+		Prefix:         "avatars",
+		MaxBytes:       int64(config.GetenvInt("AVATAR_MAX_BYTES", 5<<20)),
+		MinDimension:   64,
+		MaxDimension:   config.GetenvInt("AVATAR_MAX_DIMENSION", 4096),
+		ThumbnailSizes: user.AvatarThumbnailSizes,
+	})
+
+	campaignImageUploadService := upload.NewService(store, upload.Config{
+		Prefix:         "campaigns",
+		MaxBytes:       int64(config.GetenvInt("CAMPAIGN_IMAGE_MAX_BYTES", 10<<20)),
+		MinDimension:   200,
+		MaxDimension:   config.GetenvInt("CAMPAIGN_IMAGE_MAX_DIMENSION", 6000),
+		ThumbnailSizes: []int{},
+	})
+
+	return &app{
+		logger:                     logger,
+		db:                         db,
+		sqlDB:                      dbGorm,
+		rdb:                        config.RedisConnect(),
+		queue:                      queue,
+		store:                      store,
+		userService:                userService,
+		campaignService:            campaignService,
+		progressHub:                progressHub,
+		pledgeService:              pledgeService,
+		authService:                authService,
+		apiKeyService:              apiKeyService,
+		webhookService:             webhookService,
+		avatarUploadService:        avatarUploadService,
+		campaignImageUploadService: campaignImageUploadService,
+		shutdownHooks:              []func(context.Context) error{shutdownTracing},
+	}
+}
+
+// close menunggu pekerjaan background selesai lalu menutup koneksi pool
+// database dan redis, dipanggil setelah server berhenti menerima request
+func (a *app) close(ctx context.Context) {
+	for _, hook := range a.shutdownHooks {
+		err := hook(ctx)
+		if err != nil {
+			a.logger.Error("shutdown hook failed", "error", err)
+		}
+	}
+
+	err := a.rdb.Close()
+	if err != nil {
+		a.logger.Error("failed to close redis", "error", err)
+	}
+
+	err = a.sqlDB.Close()
+	if err != nil {
+		a.logger.Error("failed to close database", "error", err)
+	}
+}
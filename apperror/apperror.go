@@ -0,0 +1,63 @@
+package apperror
+
+import "errors"
+
+// Jenis error domain, dipetakan ke status HTTP oleh handler.HTTPErrorHandler.
+// Cek dengan errors.Is(err, apperror.ErrNotFound).
+var (
+	ErrNotFound        = errors.New("Not found")
+	ErrForbidden       = errors.New("Forbidden")
+	ErrConflict        = errors.New("Conflict")
+	ErrValidation      = errors.New("Validation failed")
+	ErrUnauthorized    = errors.New("Unauthorized")
+	ErrBadRequest      = errors.New("Bad request")
+	ErrTooManyRequests = errors.New("Too many requests")
+)
+
+// Error adalah error domain dengan kode stabil (contoh "campaign.not_found")
+// supaya client tidak perlu mem-parsing message
+type Error struct {
+	Kind    error
+	Code    string
+	Message string
+}
+
+func New(kind error, code string, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+func NotFound(code string, message string) *Error {
+	return New(ErrNotFound, code, message)
+}
+
+func Forbidden(code string, message string) *Error {
+	return New(ErrForbidden, code, message)
+}
+
+func Conflict(code string, message string) *Error {
+	return New(ErrConflict, code, message)
+}
+
+func Validation(code string, message string) *Error {
+	return New(ErrValidation, code, message)
+}
+
+func Unauthorized(code string, message string) *Error {
+	return New(ErrUnauthorized, code, message)
+}
+
+func BadRequest(code string, message string) *Error {
+	return New(ErrBadRequest, code, message)
+}
+
+func TooManyRequests(code string, message string) *Error {
+	return New(ErrTooManyRequests, code, message)
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Is(target error) bool {
+	return target == e.Kind
+}
@@ -0,0 +1,14 @@
+package auth
+
+import "github.com/golang-jwt/jwt/v5"
+
+// PurposeMFA menandai token challenge 2FA, token ini tidak bisa dipakai
+// untuk mengakses endpoint lain
+const PurposeMFA = "mfa"
+
+// Claims adalah isi JWT yang kita terbitkan
+type Claims struct {
+	UserID  int    `json:"user_id"`
+	Purpose string `json:"purpose,omitempty"`
+	jwt.RegisteredClaims
+}
@@ -0,0 +1,24 @@
+package auth
+
+import "github.com/pkg/errors"
+
+var (
+	ErrMissingToken   = errors.New("Authorization header is missing")
+	ErrMalformedToken = errors.New("Authorization header must be in the form 'Bearer <token>' or 'ApiKey <key>'")
+	ErrInvalidToken   = errors.New("Invalid token")
+	ErrExpiredToken   = errors.New("Token has expired")
+)
+
+// ErrorCode mengubah error auth menjadi kode yang bisa dibaca mesin
+func ErrorCode(err error) string {
+	switch {
+	case errors.Is(err, ErrMissingToken):
+		return "auth.missing_token"
+	case errors.Is(err, ErrMalformedToken):
+		return "auth.malformed_token"
+	case errors.Is(err, ErrExpiredToken):
+		return "auth.token_expired"
+	default:
+		return "auth.invalid_token"
+	}
+}
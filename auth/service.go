@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -9,7 +10,9 @@ import (
 
 type Service interface {
 	GenerateToken(userID int) (string, error)
-	ValidateToken(token string) (*jwt.Token, error)
+	ValidateToken(token string) (*Claims, error)
+	GenerateMFAToken(userID int) (string, error)
+	ValidateMFAToken(token string) (*Claims, error)
 }
 
 type jwtService struct {
@@ -23,9 +26,31 @@ func NewService() *jwtService {
 }
 
 func (s *jwtService) GenerateToken(userID int) (string, error) {
-	claim := jwt.MapClaims{}
-	claim["user_id"] = userID
-	claim["exp"] = time.Now().Add(time.Hour * 24).Unix()
+	return s.generate(userID, "", time.Hour*24)
+}
+
+// GenerateMFAToken dipakai saat login user yang mengaktifkan 2FA
+func (s *jwtService) GenerateMFAToken(userID int) (string, error) {
+	return s.generate(userID, PurposeMFA, time.Minute*5)
+}
+
+func (s *jwtService) ValidateToken(encodedToken string) (*Claims, error) {
+	return s.validate(encodedToken, "")
+}
+
+func (s *jwtService) ValidateMFAToken(encodedToken string) (*Claims, error) {
+	return s.validate(encodedToken, PurposeMFA)
+}
+
+func (s *jwtService) generate(userID int, purpose string, expiresIn time.Duration) (string, error) {
+	claim := Claims{
+		UserID:  userID,
+		Purpose: purpose,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiresIn)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claim)
 	signedToken, err := token.SignedString(SECRET_KEY)
@@ -36,20 +61,52 @@ func (s *jwtService) GenerateToken(userID int) (string, error) {
 	return signedToken, nil
 }
 
-func (s *jwtService) ValidateToken(encodedToken string) (*jwt.Token, error) {
-	token, err := jwt.Parse(encodedToken, func(token *jwt.Token) (interface{}, error) {
-		_, ok := token.Method.(*jwt.SigningMethodHMAC)
+func (s *jwtService) validate(encodedToken string, purpose string) (*Claims, error) {
+	claims := &Claims{}
 
-		if !ok {
-			return nil, errors.New("Invalid token")
+	token, err := jwt.ParseWithClaims(encodedToken, claims, func(token *jwt.Token) (interface{}, error) {
+		return SECRET_KEY, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, ErrExpiredToken
 		}
 
-		return []byte(SECRET_KEY), nil
-	})
+		return nil, ErrInvalidToken
+	}
+
+	// token tanpa exp atau user_id dianggap tidak valid
+	if !token.Valid || claims.ExpiresAt == nil || claims.UserID <= 0 || claims.Purpose != purpose {
+		return nil, ErrInvalidToken
+	}
 
-	if err != nil {
-		return token, err
+	return claims, nil
+}
+
+const (
+	SchemeBearer = "Bearer"
+	SchemeAPIKey = "ApiKey"
+)
+
+// ParseAuthorizationHeader mengambil scheme dan credential dari header
+// "Authorization: Bearer <token>" atau "Authorization: ApiKey <key>"
+func ParseAuthorizationHeader(authHeader string) (string, string, error) {
+	if authHeader == "" {
+		return "", "", ErrMissingToken
+	}
+
+	scheme, credential, found := strings.Cut(authHeader, " ")
+	if !found || credential == "" || strings.ContainsAny(credential, " \t") {
+		return "", "", ErrMalformedToken
 	}
 
-	return token, nil
-}
\ No newline at end of file
+	switch {
+	case strings.EqualFold(scheme, SchemeBearer):
+		return SchemeBearer, credential, nil
+	case strings.EqualFold(scheme, SchemeAPIKey):
+		return SchemeAPIKey, credential, nil
+	}
+
+	return "", "", ErrMalformedToken
+}
@@ -0,0 +1,265 @@
+package campaign
+
+import (
+	"auth-gorm-echo/metrics"
+	"auth-gorm-echo/tracing"
+	"auth-gorm-echo/user"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	cacheKeyListAll  = "campaign:list:all"
+	cacheKeyListUser = "campaign:list:user:%d"
+	cacheKeyDetail   = "campaign:detail:%d"
+	// generasi per key, dinaikkan setiap invalidasi
+	cacheKeyGeneration = "%s:gen"
+)
+
+// generasi cukup disimpan selama load dari database mungkin masih berjalan
+const generationTTL = time.Hour
+
+// setIfGeneration hanya menyimpan hasil load kalau key tidak diinvalidasi
+// selama load berjalan, kalau tidak data basi tersimpan sampai ttl habis
+var setIfGeneration = redis.NewScript(`
+local generation = redis.call('GET', KEYS[2]) or ''
+if generation ~= ARGV[2] then
+	return 0
+end
+
+redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[3])
+return 1
+`)
+
+type CacheConfig struct {
+	ListTTL   time.Duration
+	DetailTTL time.Duration
+}
+
+// CachedRepository membungkus Repository dan menyimpan hasil FindAll,
+// FindByUserID dan FindByID di redis. Semua method yang mengubah data
+// menghapus key yang terdampak setelah query ke database berhasil.
+// Kalau redis bermasalah request tetap dilayani dari database.
+//
+// Pemilik campaign tidak ikut di-cache karena nama / avatar-nya bisa berubah
+// lewat package user, FindByID selalu membacanya dari users.
+type CachedRepository struct {
+	Repository
+	users  user.Repository
+	rdb    *redis.Client
+	config CacheConfig
+	logger *slog.Logger
+	// satu query ke database per key walaupun banyak request yang miss bersamaan
+	group singleflight.Group
+}
+
+func NewCachedRepository(repository Repository, users user.Repository, rdb *redis.Client, config CacheConfig, logger *slog.Logger) *CachedRepository {
+	return &CachedRepository{Repository: repository, users: users, rdb: rdb, config: config, logger: logger}
+}
+
+func (r *CachedRepository) FindAll(ctx context.Context) ([]Campaign, error) {
+	var campaigns []Campaign
+
+	err := r.fetch(ctx, cacheKeyListAll, r.config.ListTTL, &campaigns, func(ctx context.Context) (interface{}, error) {
+		return r.Repository.FindAll(ctx)
+	})
+
+	return campaigns, err
+}
+
+func (r *CachedRepository) FindByUserID(ctx context.Context, userID int) ([]Campaign, error) {
+	var campaigns []Campaign
+
+	err := r.fetch(ctx, fmt.Sprintf(cacheKeyListUser, userID), r.config.ListTTL, &campaigns, func(ctx context.Context) (interface{}, error) {
+		return r.Repository.FindByUserID(ctx, userID)
+	})
+
+	return campaigns, err
+}
+
+func (r *CachedRepository) FindByID(ctx context.Context, ID int) (Campaign, error) {
+	campaign, err := r.findDetail(ctx, ID)
+	if err != nil {
+		return campaign, err
+	}
+
+	owner, err := r.users.FindByID(ctx, campaign.UserID)
+	if err != nil {
+		return campaign, err
+	}
+
+	campaign.User = owner
+
+	return campaign, nil
+}
+
+// findDetail membaca campaign dari cache tanpa data pemilik
+func (r *CachedRepository) findDetail(ctx context.Context, ID int) (Campaign, error) {
+	var campaign Campaign
+
+	err := r.fetch(ctx, fmt.Sprintf(cacheKeyDetail, ID), r.config.DetailTTL, &campaign, func(ctx context.Context) (interface{}, error) {
+		campaign, err := r.Repository.FindByID(ctx, ID)
+		if err != nil {
+			return nil, err
+		}
+
+		// pemilik dibaca ulang setiap request, sekaligus tidak menyimpan password / secret 2FA di redis
+		campaign.User = user.User{}
+
+		return campaign, nil
+	})
+
+	return campaign, err
+}
+
+func (r *CachedRepository) Save(ctx context.Context, campaign Campaign) (Campaign, error) {
+	newCampaign, err := r.Repository.Save(ctx, campaign)
+	if err != nil {
+		return newCampaign, err
+	}
+
+	r.invalidate(ctx, newCampaign.UserID, newCampaign.ID)
+
+	return newCampaign, nil
+}
+
+func (r *CachedRepository) CreateImage(ctx context.Context, campaignImage CampaignImage) (CampaignImage, error) {
+	newCampaignImage, err := r.Repository.CreateImage(ctx, campaignImage)
+	if err != nil {
+		return newCampaignImage, err
+	}
+
+	r.Invalidate(ctx, newCampaignImage.CampaignID)
+
+	return newCampaignImage, nil
+}
+
+func (r *CachedRepository) MarkAllImagesAsNonPrimary(ctx context.Context, campaignID int) (bool, error) {
+	ok, err := r.Repository.MarkAllImagesAsNonPrimary(ctx, campaignID)
+	if err != nil {
+		return ok, err
+	}
+
+	r.Invalidate(ctx, campaignID)
+
+	return ok, nil
+}
+
+func (r *CachedRepository) CloseExpired(ctx context.Context, now time.Time) ([]Campaign, error) {
+	campaigns, err := r.Repository.CloseExpired(ctx, now)
+	if err != nil {
+		return campaigns, err
+	}
+
+	for _, campaign := range campaigns {
+		r.invalidate(ctx, campaign.UserID, campaign.ID)
+	}
+
+	return campaigns, nil
+}
+
+// Invalidate menghapus cache detail campaign dan daftar yang memuatnya.
+// Harus dipanggil setiap kali data campaign berubah di luar repository ini,
+// contohnya saat pledge settle dan CurrentAmount / BackerCount berubah.
+func (r *CachedRepository) Invalidate(ctx context.Context, campaignID int) {
+	// pemilik dibutuhkan untuk key daftar per user, biasanya sudah ada di cache
+	campaign, err := r.findDetail(ctx, campaignID)
+	if err != nil {
+		r.logger.WarnContext(ctx, "failed to resolve campaign owner for cache invalidation", "campaign_id", campaignID, "error", err)
+		r.delete(ctx, fmt.Sprintf(cacheKeyDetail, campaignID), cacheKeyListAll)
+		return
+	}
+
+	r.invalidate(ctx, campaign.UserID, campaignID)
+}
+
+func (r *CachedRepository) invalidate(ctx context.Context, userID int, campaignID int) {
+	r.delete(ctx, fmt.Sprintf(cacheKeyDetail, campaignID), cacheKeyListAll, fmt.Sprintf(cacheKeyListUser, userID))
+}
+
+func (r *CachedRepository) delete(ctx context.Context, keys ...string) {
+	// tetap hapus walaupun request dibatalkan, cache basi lebih buruk dari miss
+	ctx = context.WithoutCancel(ctx)
+
+	_, err := r.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, keys...)
+
+		for _, key := range keys {
+			generationKey := fmt.Sprintf(cacheKeyGeneration, key)
+			pipe.Incr(ctx, generationKey)
+			pipe.Expire(ctx, generationKey, generationTTL)
+		}
+
+		return nil
+	})
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to invalidate campaign cache", "keys", keys, "error", err)
+	}
+}
+
+// fetch membaca key dari redis ke dest, kalau miss memanggil load lewat
+// single-flight lalu menyimpan hasilnya dengan ttl
+func (r *CachedRepository) fetch(ctx context.Context, key string, ttl time.Duration, dest interface{}, load func(context.Context) (interface{}, error)) error {
+	ctx, span := tracing.Start(ctx, "campaign.CachedRepository.fetch")
+	defer span.End()
+
+	cached, err := r.rdb.Get(ctx, key).Bytes()
+	if err == nil {
+		err = json.Unmarshal(cached, dest)
+		if err == nil {
+			metrics.CacheRequests.WithLabelValues("campaign", "hit").Inc()
+			return nil
+		}
+	}
+
+	if errors.Is(err, redis.Nil) {
+		metrics.CacheRequests.WithLabelValues("campaign", "miss").Inc()
+	} else {
+		metrics.CacheRequests.WithLabelValues("campaign", "error").Inc()
+		r.logger.WarnContext(ctx, "campaign cache read failed", "key", key, "error", err)
+	}
+
+	encoded, err, _ := r.group.Do(key, func() (interface{}, error) {
+		// request yang memulai load bisa dibatalkan, request lain yang menunggu tidak
+		loadCtx := context.WithoutCancel(ctx)
+
+		generationKey := fmt.Sprintf(cacheKeyGeneration, key)
+
+		// dibaca sebelum load. Kalau gagal dianggap kosong, aman karena script
+		// hanya menyimpan kalau generasi di redis juga masih kosong
+		generation, err := r.rdb.Get(loadCtx, generationKey).Result()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			r.logger.WarnContext(loadCtx, "campaign cache generation read failed", "key", key, "error", err)
+		}
+
+		value, err := load(loadCtx)
+		if err != nil {
+			return nil, err
+		}
+
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+
+		err = setIfGeneration.Run(loadCtx, r.rdb, []string{key, generationKey}, encoded, generation, ttl.Milliseconds()).Err()
+		if err != nil {
+			r.logger.WarnContext(loadCtx, "campaign cache write failed", "key", key, "error", err)
+		}
+
+		return encoded, nil
+	})
+	if err != nil {
+		tracing.RecordError(span, err)
+		return err
+	}
+
+	return json.Unmarshal(encoded.([]byte), dest)
+}
@@ -16,6 +16,8 @@ type Campaign struct {
 	GoalAmount	   int
 	CurrentAmount   int
 	Slug		   string
+	Deadline	   *time.Time
+	ClosedAt	   *time.Time
 	CreatedAt	   time.Time
 	UpdatedAt	   time.Time
 	// relation CampaignImage
@@ -0,0 +1,50 @@
+package campaign
+
+import "time"
+
+// tipe domain event campaign di outbox
+const (
+	EventCampaignCreated = "campaign.created"
+	EventCampaignClosed  = "campaign.closed"
+	// ada pledge yang settle / di-refund
+	EventCampaignBacked          = "campaign.backed"
+	EventCampaignBackingRefunded = "campaign.backing_refunded"
+	// CurrentAmount pertama kali mencapai GoalAmount
+	EventCampaignFunded = "campaign.funded"
+)
+
+// CampaignEvent adalah payload event campaign
+type CampaignEvent struct {
+	CampaignID    int        `json:"campaign_id"`
+	UserID        int        `json:"user_id"`
+	Name          string     `json:"name"`
+	Slug          string     `json:"slug"`
+	GoalAmount    int        `json:"goal_amount"`
+	CurrentAmount int        `json:"current_amount"`
+	BackerCount   int        `json:"backer_count"`
+	Deadline      *time.Time `json:"deadline"`
+	ClosedAt      *time.Time `json:"closed_at"`
+}
+
+func NewCampaignEvent(campaign Campaign) CampaignEvent {
+	return CampaignEvent{
+		CampaignID:    campaign.ID,
+		UserID:        campaign.UserID,
+		Name:          campaign.Name,
+		Slug:          campaign.Slug,
+		GoalAmount:    campaign.GoalAmount,
+		CurrentAmount: campaign.CurrentAmount,
+		BackerCount:   campaign.BackerCount,
+		Deadline:      campaign.Deadline,
+		ClosedAt:      campaign.ClosedAt,
+	}
+}
+
+// BackingEvent adalah payload campaign.backed dan campaign.backing_refunded,
+// CurrentAmount / BackerCount sudah termasuk perubahan dari pledge ini
+type BackingEvent struct {
+	CampaignEvent
+	Amount int `json:"amount"`
+	// kosong kalau backer tidak mau namanya ditampilkan
+	BackerName string `json:"backer_name"`
+}
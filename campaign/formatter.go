@@ -1,6 +1,10 @@
 package campaign
 
-import "strings"
+import (
+	"auth-gorm-echo/storage"
+	"strings"
+	"time"
+)
 
 type CampaignFormatter struct {
 	ID               int    `json:"id"`
@@ -11,6 +15,9 @@ type CampaignFormatter struct {
 	GoalAmount 	int `json:"goal_amount"`
 	CurrentAmount 	int `json:"current_amount"`
 	Slug 		string `json:"slug"`
+	Deadline 	*time.Time `json:"deadline"`
+	IsClosed 	bool `json:"is_closed"`
+	IsOwner 	bool `json:"is_owner"`
 }
 
 func FormatCampaign(campaign Campaign) CampaignFormatter {
@@ -23,9 +30,11 @@ func FormatCampaign(campaign Campaign) CampaignFormatter {
 	campaignFormatter.GoalAmount = campaign.GoalAmount
 	campaignFormatter.CurrentAmount = campaign.CurrentAmount
 	campaignFormatter.Slug = campaign.Slug
+	campaignFormatter.Deadline = campaign.Deadline
+	campaignFormatter.IsClosed = campaign.ClosedAt != nil
 
 	if len(campaign.CampaignImages) > 0 {
-		campaignFormatter.ImageURL = campaign.CampaignImages[0].FileName
+		campaignFormatter.ImageURL = storage.URL(campaign.CampaignImages[0].FileName)
 	}
 
 	return campaignFormatter
@@ -53,8 +62,11 @@ type CampaignDetailFormatter struct {
 	UserID 		int `json:"user_id"`
 	Slug 		string `json:"slug"`
 	Perks 		[]string `json:"perks"`
+	Deadline 	*time.Time `json:"deadline"`
+	IsClosed 	bool `json:"is_closed"`
 	User 		CampaignUserFormatter `json:"user"`
 	Images 		[]CampaignImageFormatter `json:"images"`
+	IsOwner 	bool `json:"is_owner"`
 }
 
 type CampaignUserFormatter struct {
@@ -79,9 +91,11 @@ func FormatCampaignDetail(campaign Campaign) CampaignDetailFormatter {
 	campaignDetailFormatter.CurrentAmount = campaign.CurrentAmount
 	campaignDetailFormatter.UserID = campaign.UserID
 	campaignDetailFormatter.Slug = campaign.Slug
+	campaignDetailFormatter.Deadline = campaign.Deadline
+	campaignDetailFormatter.IsClosed = campaign.ClosedAt != nil
 
 	if len(campaign.CampaignImages) > 0 {
-		campaignDetailFormatter.ImageURL = campaign.CampaignImages[0].FileName
+		campaignDetailFormatter.ImageURL = storage.URL(campaign.CampaignImages[0].FileName)
 	}
 
 	var perks []string
@@ -96,7 +110,7 @@ func FormatCampaignDetail(campaign Campaign) CampaignDetailFormatter {
 
 	campaignUserFormatter := CampaignUserFormatter{}
 	campaignUserFormatter.Name = user.Name
-	campaignUserFormatter.ImageURL = user.AvatarFileName
+	campaignUserFormatter.ImageURL = storage.URL(user.AvatarFileName)
 
 	campaignDetailFormatter.User = campaignUserFormatter
 
@@ -104,7 +118,7 @@ func FormatCampaignDetail(campaign Campaign) CampaignDetailFormatter {
 
 	for _, image := range campaign.CampaignImages {
 		campaignImageFormatter := CampaignImageFormatter{}
-		campaignImageFormatter.ImageURL = image.FileName
+		campaignImageFormatter.ImageURL = storage.URL(image.FileName)
 
 		isPrimary := false
 
@@ -1,6 +1,9 @@
 package campaign
 
-import "auth-gorm-echo/user"
+import (
+	"auth-gorm-echo/user"
+	"time"
+)
 
 type GetCampaignDetailInput struct {
 	ID int `param:"id" validate:"required"`
@@ -12,5 +15,12 @@ type CreateCampaignInput struct {
 	Description string `json:"description" validate:"required"`
 	GoalAmount int `json:"goal_amount" validate:"required"`
 	Perks string `json:"perks" validate:"required"`
+	Deadline *time.Time `json:"deadline"`
 	User 	user.User 
-}
\ No newline at end of file
+}
+
+type CreateCampaignImageInput struct {
+	CampaignID int `form:"campaign_id" validate:"required"`
+	IsPrimary bool `form:"is_primary"`
+	User 	user.User
+}
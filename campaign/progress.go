@@ -0,0 +1,183 @@
+package campaign
+
+import (
+	"auth-gorm-echo/outbox"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	progressChannel        = "campaign:progress:%d"
+	progressChannelPattern = "campaign:progress:*"
+)
+
+// buffer per subscriber, update untuk client yang terlalu lambat dibuang
+const progressBuffer = 16
+
+// Progress dikirim ke client stream setiap kali transaksi campaign settle
+type Progress struct {
+	CampaignID    int `json:"campaign_id"`
+	CurrentAmount int `json:"current_amount"`
+	BackerCount   int `json:"backer_count"`
+	// nama backer baru yang bersedia ditampilkan publik
+	NewBackers []string `json:"new_backers"`
+}
+
+// ProgressHub menyebarkan Progress ke semua client stream. Publish lewat
+// redis pub/sub sehingga update dari satu instance sampai ke client yang
+// terhubung ke instance lain. Setiap instance hanya memakai satu koneksi
+// PSUBSCRIBE lalu membagikan pesan ke subscriber lokal.
+type ProgressHub struct {
+	rdb    *redis.Client
+	logger *slog.Logger
+
+	mu          sync.Mutex
+	subscribers map[int]map[chan Progress]struct{}
+	closed      bool
+}
+
+func NewProgressHub(rdb *redis.Client, logger *slog.Logger) *ProgressHub {
+	return &ProgressHub{rdb: rdb, logger: logger, subscribers: map[int]map[chan Progress]struct{}{}}
+}
+
+// PublishBacking adalah subscriber outbox campaign.backed dan
+// campaign.backing_refunded, dijalankan setelah pledge settle / di-refund
+func (h *ProgressHub) PublishBacking(ctx context.Context, event outbox.Event) error {
+	var payload BackingEvent
+
+	err := event.Decode(&payload)
+	if err != nil {
+		return err
+	}
+
+	progress := Progress{
+		CampaignID:    payload.CampaignID,
+		CurrentAmount: payload.CurrentAmount,
+		BackerCount:   payload.BackerCount,
+	}
+	if event.Type == EventCampaignBacked && payload.BackerName != "" {
+		progress.NewBackers = []string{payload.BackerName}
+	}
+
+	return h.Publish(ctx, progress)
+}
+
+func (h *ProgressHub) Publish(ctx context.Context, progress Progress) error {
+	if progress.NewBackers == nil {
+		progress.NewBackers = []string{}
+	}
+
+	payload, err := json.Marshal(progress)
+	if err != nil {
+		return err
+	}
+
+	return h.rdb.Publish(ctx, fmt.Sprintf(progressChannel, progress.CampaignID), payload).Err()
+}
+
+// Subscribe mendaftarkan client untuk satu campaign. Channel ditutup saat
+// hub berhenti, fungsi yang dikembalikan wajib dipanggil saat client putus.
+func (h *ProgressHub) Subscribe(campaignID int) (<-chan Progress, func()) {
+	updates := make(chan Progress, progressBuffer)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		close(updates)
+		return updates, func() {}
+	}
+
+	if h.subscribers[campaignID] == nil {
+		h.subscribers[campaignID] = map[chan Progress]struct{}{}
+	}
+	h.subscribers[campaignID][updates] = struct{}{}
+
+	unsubscribe := func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+
+		if _, ok := h.subscribers[campaignID][updates]; !ok {
+			return
+		}
+
+		delete(h.subscribers[campaignID], updates)
+		if len(h.subscribers[campaignID]) == 0 {
+			delete(h.subscribers, campaignID)
+		}
+		close(updates)
+	}
+
+	return updates, unsubscribe
+}
+
+// Run menerima pesan dari redis sampai ctx dibatalkan, lalu menutup semua
+// subscriber supaya koneksi stream selesai sebelum server shutdown
+func (h *ProgressHub) Run(ctx context.Context) {
+	pubsub := h.rdb.PSubscribe(ctx, progressChannelPattern)
+	defer pubsub.Close()
+	defer h.closeAll()
+
+	// go-redis otomatis reconnect, pesan selama koneksi putus hilang dan
+	// client mendapat nilai terbaru pada update berikutnya
+	messages := pubsub.Channel()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case message, ok := <-messages:
+			if !ok {
+				return
+			}
+
+			h.dispatch(ctx, message)
+		}
+	}
+}
+
+func (h *ProgressHub) dispatch(ctx context.Context, message *redis.Message) {
+	campaignID, err := strconv.Atoi(strings.TrimPrefix(message.Channel, strings.TrimSuffix(progressChannelPattern, "*")))
+	if err != nil {
+		return
+	}
+
+	var progress Progress
+	err = json.Unmarshal([]byte(message.Payload), &progress)
+	if err != nil {
+		h.logger.WarnContext(ctx, "invalid campaign progress message", "channel", message.Channel, "error", err)
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for updates := range h.subscribers[campaignID] {
+		select {
+		case updates <- progress:
+		default:
+			h.logger.WarnContext(ctx, "dropping campaign progress for slow client", "campaign_id", campaignID)
+		}
+	}
+}
+
+func (h *ProgressHub) closeAll() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for campaignID, subscribers := range h.subscribers {
+		for updates := range subscribers {
+			close(updates)
+		}
+		delete(h.subscribers, campaignID)
+	}
+
+	h.closed = true
+}
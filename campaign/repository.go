@@ -1,60 +1,299 @@
 package campaign
 
-import "gorm.io/gorm"
+import (
+	"auth-gorm-echo/outbox"
+	"auth-gorm-echo/tracing"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
 
 type Repository interface {
-	FindAll() ([]Campaign, error)
-	FindByUserID(userID int) ([]Campaign, error)
-	FindByID(ID int) (Campaign, error)
-	Save(campaign Campaign) (Campaign, error)
+	FindAll(ctx context.Context) ([]Campaign, error)
+	FindByUserID(ctx context.Context, userID int) ([]Campaign, error)
+	FindByID(ctx context.Context, ID int) (Campaign, error)
+	Save(ctx context.Context, campaign Campaign) (Campaign, error)
+	CreateImage(ctx context.Context, campaignImage CampaignImage) (CampaignImage, error)
+	MarkAllImagesAsNonPrimary(ctx context.Context, campaignID int) (bool, error)
+	CloseExpired(ctx context.Context, now time.Time) ([]Campaign, error)
+	FindProgress(ctx context.Context, ID int) (Progress, error)
 }
 
 type repository struct {
-	db *gorm.DB
+	db      *gorm.DB
+	timeout time.Duration
+}
+
+func NewRepository(db *gorm.DB, timeout time.Duration) *repository {
+	return &repository{db, timeout}
 }
 
-func NewRepository(db *gorm.DB) *repository {
-	return &repository{db}
+// withTimeout menerapkan batas waktu DB_QUERY_TIMEOUT ke setiap query
+func (r *repository) withTimeout(ctx context.Context) (*gorm.DB, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	return r.db.WithContext(ctx), cancel
 }
 
-func (r *repository) FindAll() ([]Campaign, error) {
+func (r *repository) FindAll(ctx context.Context) ([]Campaign, error) {
+	ctx, span := tracing.Start(ctx, "campaign.Repository.FindAll")
+	defer span.End()
+
+	db, cancel := r.withTimeout(ctx)
+	defer cancel()
+
 	var campaigns []Campaign
 
-	err := r.db.Preload("CampaignImages", "campaign_images.is_primary = 1").Find(&campaigns).Error
+	err := db.Preload("CampaignImages", "campaign_images.is_primary = 1").Find(&campaigns).Error
 	if err != nil {
+		tracing.RecordError(span, err)
 		return campaigns, err
 	}
 
 	return campaigns, nil
 }
 
-func (r *repository) FindByUserID(UserID int) ([]Campaign, error) {
+func (r *repository) FindByUserID(ctx context.Context, UserID int) ([]Campaign, error) {
+	ctx, span := tracing.Start(ctx, "campaign.Repository.FindByUserID")
+	defer span.End()
+
+	db, cancel := r.withTimeout(ctx)
+	defer cancel()
+
 	var campaigns []Campaign
 
-	err := r.db.Where("user_id = ?", UserID).Preload("CampaignImages", "campaign_images.is_primary = 1").Find(&campaigns).Error
+	err := db.Where("user_id = ?", UserID).Preload("CampaignImages", "campaign_images.is_primary = 1").Find(&campaigns).Error
 	if err != nil {
+		tracing.RecordError(span, err)
 		return campaigns, err
 	}
 
 	return campaigns, nil
 }
 
-func (r *repository) FindByID(ID int) (Campaign, error) {
+func (r *repository) FindByID(ctx context.Context, ID int) (Campaign, error) {
+	ctx, span := tracing.Start(ctx, "campaign.Repository.FindByID")
+	defer span.End()
+
+	db, cancel := r.withTimeout(ctx)
+	defer cancel()
+
 	var campaign Campaign
 
-	err := r.db.Where("id = ?", ID).Preload("CampaignImages").Preload("User").Find(&campaign).Error
+	err := db.Where("id = ?", ID).Preload("CampaignImages").Preload("User").First(&campaign).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return campaign, ErrCampaignNotFound
+	}
+	if err != nil {
+		tracing.RecordError(span, err)
+		return campaign, err
+	}
+
+	return campaign, nil
+}
+
+func (r *repository) Save(ctx context.Context, campaign Campaign) (Campaign, error) {
+	ctx, span := tracing.Start(ctx, "campaign.Repository.Save")
+	defer span.End()
+
+	db, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Create(&campaign).Error
+		if err != nil {
+			return err
+		}
+
+		return outbox.Add(tx, EventCampaignCreated, campaign.ID, NewCampaignEvent(campaign))
+	})
+	if err != nil {
+		tracing.RecordError(span, err)
+		return campaign, err
+	}
+
+	return campaign, nil
+}
+
+func (r *repository) CreateImage(ctx context.Context, campaignImage CampaignImage) (CampaignImage, error) {
+	ctx, span := tracing.Start(ctx, "campaign.Repository.CreateImage")
+	defer span.End()
+
+	db, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	// updated_at campaign ikut berubah supaya ETag campaign ikut berganti
+	err := db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Create(&campaignImage).Error
+		if err != nil {
+			return err
+		}
+
+		return touch(tx, campaignImage.CampaignID)
+	})
+	if err != nil {
+		tracing.RecordError(span, err)
+		return campaignImage, err
+	}
+
+	return campaignImage, nil
+}
+
+func (r *repository) MarkAllImagesAsNonPrimary(ctx context.Context, campaignID int) (bool, error) {
+	ctx, span := tracing.Start(ctx, "campaign.Repository.MarkAllImagesAsNonPrimary")
+	defer span.End()
+
+	db, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&CampaignImage{}).Where("campaign_id = ?", campaignID).Update("is_primary", false).Error
+		if err != nil {
+			return err
+		}
+
+		return touch(tx, campaignID)
+	})
+	if err != nil {
+		tracing.RecordError(span, err)
+		return false, err
+	}
+
+	return true, nil
+}
+
+// CloseExpired menutup campaign yang deadline-nya sudah lewat dan
+// mengembalikan campaign yang baru ditutup
+func (r *repository) CloseExpired(ctx context.Context, now time.Time) ([]Campaign, error) {
+	ctx, span := tracing.Start(ctx, "campaign.Repository.CloseExpired")
+	defer span.End()
+
+	db, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	var campaigns []Campaign
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&campaigns).
+			Clauses(clause.Returning{}).
+			Where("closed_at IS NULL AND deadline IS NOT NULL AND deadline < ?", now).
+			Update("closed_at", now).Error
+		if err != nil {
+			return err
+		}
+
+		for _, campaign := range campaigns {
+			err = outbox.Add(tx, EventCampaignClosed, campaign.ID, NewCampaignEvent(campaign))
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		tracing.RecordError(span, err)
+		return campaigns, err
+	}
+
+	return campaigns, nil
+}
+
+// AddBacking menambah CurrentAmount dan BackerCount di dalam transaksi
+// settle pledge lalu menulis campaign.backed, ditambah campaign.funded kalau
+// backing ini yang membuat campaign mencapai target. Cache campaign harus
+// diinvalidasi setelah transaksi di-commit.
+func AddBacking(tx *gorm.DB, campaignID int, amount int, backerName string) (Campaign, error) {
+	campaign, err := adjustFunding(tx, campaignID, amount, 1)
+	if err != nil {
+		return campaign, err
+	}
+
+	event := NewCampaignEvent(campaign)
+
+	err = outbox.Add(tx, EventCampaignBacked, campaign.ID, BackingEvent{CampaignEvent: event, Amount: amount, BackerName: backerName})
 	if err != nil {
 		return campaign, err
 	}
 
+	// hanya backing yang melewati target, campaign.funded tidak terkirim lagi setelahnya
+	if campaign.CurrentAmount >= campaign.GoalAmount && campaign.CurrentAmount-amount < campaign.GoalAmount {
+		err = outbox.Add(tx, EventCampaignFunded, campaign.ID, event)
+		if err != nil {
+			return campaign, err
+		}
+	}
+
 	return campaign, nil
 }
 
-func (r *repository) Save(campaign Campaign) (Campaign, error) {
-	err := r.db.Create(&campaign).Error
+// RemoveBacking kebalikan AddBacking saat pledge di-refund. Campaign yang
+// sudah pernah funded tidak mengirim event lagi walaupun turun di bawah target.
+func RemoveBacking(tx *gorm.DB, campaignID int, amount int) (Campaign, error) {
+	campaign, err := adjustFunding(tx, campaignID, -amount, -1)
+	if err != nil {
+		return campaign, err
+	}
+
+	err = outbox.Add(tx, EventCampaignBackingRefunded, campaign.ID, BackingEvent{CampaignEvent: NewCampaignEvent(campaign), Amount: amount})
 	if err != nil {
 		return campaign, err
 	}
 
 	return campaign, nil
-}
\ No newline at end of file
+}
+
+func adjustFunding(tx *gorm.DB, campaignID int, amount int, backers int) (Campaign, error) {
+	var campaign Campaign
+
+	result := tx.Model(&campaign).
+		Clauses(clause.Returning{}).
+		Where("id = ?", campaignID).
+		Updates(map[string]interface{}{
+			"current_amount": gorm.Expr("current_amount + ?", amount),
+			"backer_count":   gorm.Expr("backer_count + ?", backers),
+		})
+	if result.Error != nil {
+		return campaign, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return campaign, ErrCampaignNotFound
+	}
+
+	return campaign, nil
+}
+
+// FindProgress membaca nilai pendanaan terbaru. Tidak di-cache oleh
+// CachedRepository karena dipakai sebagai snapshot awal stream progress.
+func (r *repository) FindProgress(ctx context.Context, ID int) (Progress, error) {
+	ctx, span := tracing.Start(ctx, "campaign.Repository.FindProgress")
+	defer span.End()
+
+	db, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	var campaign Campaign
+
+	err := db.Select("id", "current_amount", "backer_count").Where("id = ?", ID).Take(&campaign).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return Progress{}, ErrCampaignNotFound
+	}
+	if err != nil {
+		tracing.RecordError(span, err)
+		return Progress{}, err
+	}
+
+	return Progress{
+		CampaignID:    campaign.ID,
+		CurrentAmount: campaign.CurrentAmount,
+		BackerCount:   campaign.BackerCount,
+		NewBackers:    []string{},
+	}, nil
+}
+
+// touch memperbarui updated_at campaign saat data turunannya (gambar) berubah
+func touch(db *gorm.DB, campaignID int) error {
+	return db.Model(&Campaign{}).Where("id = ?", campaignID).Update("updated_at", time.Now()).Error
+}
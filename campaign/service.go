@@ -1,28 +1,47 @@
 package campaign
 
 import (
+	"auth-gorm-echo/apperror"
+	"auth-gorm-echo/metrics"
+	"auth-gorm-echo/tracing"
+	"context"
 	"fmt"
+	"log/slog"
+	"time"
 
 	"github.com/gosimple/slug"
 )
 
+var (
+	ErrCampaignNotFound = apperror.NotFound("campaign.not_found", "Campaign not found")
+	ErrNotCampaignOwner = apperror.Forbidden("campaign.not_owner", "Not an owner of the campaign")
+	ErrDeadlineInPast   = apperror.Validation("campaign.deadline_in_past", "Deadline must be in the future")
+)
+
 type Service interface {
-	GetCampaigns(userID int) ([]Campaign, error)
-	GetCampaignByID(input GetCampaignDetailInput) (Campaign, error)
-	CreateCampaign(input CreateCampaignInput) (Campaign, error)
+	GetCampaigns(ctx context.Context, userID int) ([]Campaign, error)
+	GetCampaignByID(ctx context.Context, input GetCampaignDetailInput) (Campaign, error)
+	GetCampaignProgress(ctx context.Context, input GetCampaignDetailInput) (Progress, error)
+	CreateCampaign(ctx context.Context, input CreateCampaignInput) (Campaign, error)
+	SaveCampaignImage(ctx context.Context, input CreateCampaignImageInput, fileLocation string) (CampaignImage, error)
+	CloseExpiredCampaigns(ctx context.Context) ([]Campaign, error)
 }
 
 type service struct {
 	repository Repository
+	logger     *slog.Logger
 }
 
-func NewService(repository Repository) *service {
-	return &service{repository}
+func NewService(repository Repository, logger *slog.Logger) *service {
+	return &service{repository, logger}
 }
 
-func (s *service) GetCampaigns(userID int) ([]Campaign, error) {
+func (s *service) GetCampaigns(ctx context.Context, userID int) ([]Campaign, error) {
+	ctx, span := tracing.Start(ctx, "campaign.Service.GetCampaigns")
+	defer span.End()
+
 	if userID != 0 {
-		campaigns, err := s.repository.FindByUserID(userID)
+		campaigns, err := s.repository.FindByUserID(ctx, userID)
 		if err != nil {
 			return campaigns, err
 		}
@@ -30,7 +49,7 @@ func (s *service) GetCampaigns(userID int) ([]Campaign, error) {
 		return campaigns, nil
 	}
 
-	campaigns, err := s.repository.FindAll()
+	campaigns, err := s.repository.FindAll(ctx)
 	if err != nil {
 		return campaigns, err
 	}
@@ -38,8 +57,11 @@ func (s *service) GetCampaigns(userID int) ([]Campaign, error) {
 	return campaigns, nil
 }
 
-func (s *service) GetCampaignByID(input GetCampaignDetailInput) (Campaign, error) {
-	campaign, err := s.repository.FindByID(input.ID)
+func (s *service) GetCampaignByID(ctx context.Context, input GetCampaignDetailInput) (Campaign, error) {
+	ctx, span := tracing.Start(ctx, "campaign.Service.GetCampaignByID")
+	defer span.End()
+
+	campaign, err := s.repository.FindByID(ctx, input.ID)
 
 	if err != nil {
 		return campaign, err
@@ -48,7 +70,19 @@ func (s *service) GetCampaignByID(input GetCampaignDetailInput) (Campaign, error
 	return campaign, nil
 }
 
-func (s *service) CreateCampaign(input CreateCampaignInput) (Campaign, error) {
+// GetCampaignProgress selalu membaca dari database, cache detail bisa
+// tertinggal sampai DetailTTL dari update yang dikirim lewat stream
+func (s *service) GetCampaignProgress(ctx context.Context, input GetCampaignDetailInput) (Progress, error) {
+	ctx, span := tracing.Start(ctx, "campaign.Service.GetCampaignProgress")
+	defer span.End()
+
+	return s.repository.FindProgress(ctx, input.ID)
+}
+
+func (s *service) CreateCampaign(ctx context.Context, input CreateCampaignInput) (Campaign, error) {
+	ctx, span := tracing.Start(ctx, "campaign.Service.CreateCampaign")
+	defer span.End()
+
 	campaign := Campaign{}
 	campaign.Name = input.Name
 	campaign.ShortDescription = input.ShortDescription
@@ -57,13 +91,77 @@ func (s *service) CreateCampaign(input CreateCampaignInput) (Campaign, error) {
 	campaign.Perks = input.Perks
 	campaign.UserID = input.User.ID
 
+	if input.Deadline != nil {
+		if input.Deadline.Before(time.Now()) {
+			return campaign, ErrDeadlineInPast
+		}
+
+		campaign.Deadline = input.Deadline
+	}
+
 	slugCandidate := fmt.Sprintf("%s %d", input.Name, input.User.ID)
 	campaign.Slug = slug.Make(slugCandidate)
 
-	newCampaign, err := s.repository.Save(campaign)
+	newCampaign, err := s.repository.Save(ctx, campaign)
 	if err != nil {
 		return newCampaign, err
 	}
 
+	metrics.CampaignsCreated.Inc()
+	s.logger.InfoContext(ctx, "campaign created", "campaign_id", newCampaign.ID, "slug", newCampaign.Slug)
+
 	return newCampaign, nil
-}
\ No newline at end of file
+}
+
+// SaveCampaignImage
+func (s *service) SaveCampaignImage(ctx context.Context, input CreateCampaignImageInput, fileLocation string) (CampaignImage, error) {
+	ctx, span := tracing.Start(ctx, "campaign.Service.SaveCampaignImage")
+	defer span.End()
+
+	campaign, err := s.repository.FindByID(ctx, input.CampaignID)
+	if err != nil {
+		return CampaignImage{}, err
+	}
+
+	if campaign.UserID != input.User.ID {
+		return CampaignImage{}, ErrNotCampaignOwner
+	}
+
+	isPrimary := 0
+
+	if input.IsPrimary {
+		isPrimary = 1
+
+		_, err := s.repository.MarkAllImagesAsNonPrimary(ctx, input.CampaignID)
+		if err != nil {
+			return CampaignImage{}, err
+		}
+	}
+
+	campaignImage := CampaignImage{}
+	campaignImage.CampaignID = input.CampaignID
+	campaignImage.IsPrimary = isPrimary
+	campaignImage.FileName = fileLocation
+
+	newCampaignImage, err := s.repository.CreateImage(ctx, campaignImage)
+	if err != nil {
+		return newCampaignImage, err
+	}
+
+	return newCampaignImage, nil
+}
+
+// CloseExpiredCampaigns dijalankan berkala (campaign close-expired)
+func (s *service) CloseExpiredCampaigns(ctx context.Context) ([]Campaign, error) {
+	ctx, span := tracing.Start(ctx, "campaign.Service.CloseExpiredCampaigns")
+	defer span.End()
+
+	campaigns, err := s.repository.CloseExpired(ctx, time.Now())
+	if err != nil {
+		return campaigns, err
+	}
+
+	s.logger.InfoContext(ctx, "expired campaigns closed", "count", len(campaigns))
+
+	return campaigns, nil
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"auth-gorm-echo/config"
+	"auth-gorm-echo/docs"
+	"auth-gorm-echo/logging"
+	"auth-gorm-echo/migration"
+	"auth-gorm-echo/seed"
+	"auth-gorm-echo/user"
+	"auth-gorm-echo/validation"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+)
+
+func runServe(args []string) {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := flags.String("addr", config.Getenv("APP_ADDR", ":9000"), "address to listen on")
+	flags.Parse(args)
+
+	serve(newApp(), *addr)
+}
+
+// runMigrate: migrate up [n] | migrate down [n] | migrate status
+func runMigrate(args []string) {
+	if len(args) == 0 {
+		exitUsage("usage: server migrate up|down|status [n]")
+	}
+
+	// migrate hanya butuh database, tidak perlu redis / storage
+	config.DatabaseInit()
+
+	sqlDB, err := config.GetDB().DB()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	migrator, err := migration.NewMigrator(sqlDB)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	steps := 0
+	if len(args) > 1 {
+		steps, err = strconv.Atoi(args[1])
+		if err != nil || steps < 1 {
+			log.Fatalf("invalid number of steps %q", args[1])
+		}
+	}
+
+	switch args[0] {
+	case "up":
+		done, err := migrator.Up(steps)
+		for _, m := range done {
+			fmt.Printf("migrated up   %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if len(done) == 0 {
+			fmt.Println("nothing to migrate")
+		}
+	case "down":
+		// down tanpa angka hanya rollback satu migration supaya tidak menghapus semua tabel
+		if steps == 0 {
+			steps = 1
+		}
+
+		done, err := migrator.Down(steps)
+		for _, m := range done {
+			fmt.Printf("migrated down %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.AppliedAt != nil {
+				appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+
+			fmt.Printf("%04d_%-35s %s\n", status.Migration.Version, status.Migration.Name, appliedAt)
+		}
+	default:
+		exitUsage("usage: server migrate up|down|status [n]")
+	}
+}
+
+func runSeed(args []string) {
+	app := newApp()
+
+	seeder := seed.NewSeeder(app.db, app.userService, app.campaignService, app.avatarUploadService, app.campaignImageUploadService)
+
+	err := seeder.Run(context.Background())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Printf("seed finished, demo users can log in with password %q\n", seed.DemoPassword)
+}
+
+// runUser: user create --name --email --password [--occupation] [--admin]
+func runUser(args []string) {
+	if len(args) == 0 || args[0] != "create" {
+		exitUsage("usage: server user create --name <name> --email <email> --password <password> [--occupation <occupation>] [--admin]")
+	}
+
+	flags := flag.NewFlagSet("user create", flag.ExitOnError)
+	name := flags.String("name", "", "full name")
+	email := flags.String("email", "", "email address")
+	password := flags.String("password", "", "password")
+	occupation := flags.String("occupation", "-", "occupation")
+	admin := flags.Bool("admin", false, "create the user with the admin role")
+	flags.Parse(args[1:])
+
+	if *name == "" || *email == "" || *password == "" {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	app := newApp()
+
+	input := user.RegisterUserInput{
+		Name:       *name,
+		Occupation: *occupation,
+		Email:      *email,
+		Password:   *password,
+	}
+
+	if *admin {
+		input.Role = "admin"
+	}
+
+	// validasi yang sama dengan endpoint register
+	err := validation.New().Validate(&input)
+	if err != nil {
+		var validationErr *validation.Error
+		if errors.As(err, &validationErr) {
+			log.Fatalf("invalid input: %v", validationErr.Fields(validation.Translator("en")))
+		}
+
+		log.Fatal(err)
+	}
+
+	isEmailAvailable, err := app.userService.IsEmailAvailable(context.Background(), user.CheckEmailInput{Email: input.Email})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if !isEmailAvailable {
+		log.Fatalf("email %s has been registered", input.Email)
+	}
+
+	newUser, err := app.userService.RegisterUser(context.Background(), input)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Printf("created user %d (%s) with role %s\n", newUser.ID, newUser.Email, newUser.Role)
+}
+
+// runCampaign: campaign close-expired
+func runCampaign(args []string) {
+	if len(args) == 0 || args[0] != "close-expired" {
+		exitUsage("usage: server campaign close-expired")
+	}
+
+	app := newApp()
+
+	campaigns, err := app.campaignService.CloseExpiredCampaigns(context.Background())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, campaign := range campaigns {
+		fmt.Printf("closed campaign %d (%s)\n", campaign.ID, campaign.Slug)
+	}
+
+	fmt.Printf("%d campaign(s) closed\n", len(campaigns))
+}
+
+// runToken: token issue --user-id <id>
+func runToken(args []string) {
+	if len(args) == 0 || args[0] != "issue" {
+		exitUsage("usage: server token issue --user-id <id>")
+	}
+
+	flags := flag.NewFlagSet("token issue", flag.ExitOnError)
+	userID := flags.Int("user-id", 0, "ID of the user")
+	flags.Parse(args[1:])
+
+	if *userID <= 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	app := newApp()
+
+	existingUser, err := app.userService.GetUserByID(context.Background(), *userID)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	token, err := app.authService.GenerateToken(existingUser.ID)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(token)
+}
+
+// runDocs: docs check | docs print
+func runDocs(args []string) {
+	if len(args) == 0 {
+		exitUsage("usage: server docs check|print")
+	}
+
+	// router hanya dibangun untuk dibaca, tidak butuh database / redis
+	router, _ := newRouter(&app{logger: logging.New()})
+
+	switch args[0] {
+	case "check":
+		err := docs.Verify(router.Routes(), docs.Operations)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("openapi document covers all %d routes\n", len(docs.Operations))
+	case "print":
+		output, err := json.MarshalIndent(docs.Build(docs.Operations, apiKeyScopes), "", "  ")
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		fmt.Println(string(output))
+	default:
+		exitUsage("usage: server docs check|print")
+	}
+}
+
+func exitUsage(message string) {
+	fmt.Fprintln(os.Stderr, message)
+	os.Exit(2)
+}
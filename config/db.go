@@ -11,11 +11,11 @@ var db *gorm.DB
 var err error
 
 func DatabaseInit() {
-	host := "localhost"
-	user := "postgres"
-	password := "secret"
-	dbName := "db_crowdfunding"
-	port := 5432
+	host := Getenv("DB_HOST", "localhost")
+	user := Getenv("DB_USER", "postgres")
+	password := Getenv("DB_PASSWORD", "secret")
+	dbName := Getenv("DB_NAME", "db_crowdfunding")
+	port := GetenvInt("DB_PORT", 5432)
 
 	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%d sslmode=disable TimeZone=Asia/Jakarta", host, user, password, dbName, port)
 	db, err = gorm.Open(postgres.Open(dsn), &gorm.Config{})
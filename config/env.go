@@ -0,0 +1,36 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// Getenv membaca environment variable, kalau kosong pakai nilai default
+func Getenv(key string, fallback string) string {
+	value, ok := os.LookupEnv(key)
+	if !ok || value == "" {
+		return fallback
+	}
+
+	return value
+}
+
+func GetenvInt(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+
+	return value
+}
+
+// GetenvDuration menerima format time.ParseDuration, contoh "15m" atau "1h"
+func GetenvDuration(key string, fallback time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+
+	return value
+}
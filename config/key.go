@@ -0,0 +1,23 @@
+package config
+
+import (
+	"encoding/base64"
+	"errors"
+)
+
+// AppKey membaca APP_KEY, 32 byte dalam base64 (buat dengan `openssl rand -base64 32`).
+// Dipakai untuk mengenkripsi data sensitif di database, jangan diganti
+// setelah ada data yang terenkripsi.
+func AppKey() ([]byte, error) {
+	value := Getenv("APP_KEY", "")
+	if value == "" {
+		return nil, errors.New("APP_KEY is not set, generate one with `openssl rand -base64 32`")
+	}
+
+	key, err := base64.StdEncoding.DecodeString(value)
+	if err != nil || len(key) != 32 {
+		return nil, errors.New("APP_KEY must be 32 bytes encoded as base64")
+	}
+
+	return key, nil
+}
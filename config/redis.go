@@ -1,7 +1,6 @@
 package config
 
 import (
-	"context"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -10,14 +9,13 @@ import (
 var (
 		rdb *redis.Client
 		sessionExp   = time.Hour * 24
-		redisCtx     = context.Background()
 	)
 
 func RedisInit() error {
 	dbRedis := redis.NewClient(&redis.Options{
-		Addr:     "localhost:6379",
-		Password: "", // no password set
-		DB:       3,  // database 3
+		Addr:     Getenv("REDIS_ADDR", "localhost:6379"),
+		Password: Getenv("REDIS_PASSWORD", ""), // default no password set
+		DB:       GetenvInt("REDIS_DB", 3),  // default database 3
 	})
 
 	rdb = dbRedis
@@ -30,8 +28,4 @@ func RedisConnect() *redis.Client {
 
 func GetsessionExp() time.Duration {
 	return sessionExp
-}
-
-func GetRedisCtx() context.Context {
-	return redisCtx
 }
\ No newline at end of file
@@ -1,39 +0,0 @@
-create database db_crowdfunding;
-
-use db_crowdfunding;
--- create table
-CREATE TABLE users (
-    id SERIAL PRIMARY KEY,
-    name VARCHAR(255) NOT NULL,
-    occupation VARCHAR(255) NOT NULL,
-    email VARCHAR(255) NOT NULL,
-    password VARCHAR(255) NOT NULL,
-    avatar_file_name VARCHAR(255) NULL,
-    role VARCHAR(255) NOT NULL,
-    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
-);
-
-CREATE TABLE campaigns (
-    id SERIAL PRIMARY KEY,
-    user_id INT NOT NULL,
-    name VARCHAR(255) NOT NULL,
-    short_description VARCHAR(255) NOT NULL,
-    description TEXT NOT NULL,
-    perks TEXT NOT NULL,
-    backer_count INT NOT NULL,
-    goal_amount INT NOT NULL,
-    current_amount INT NOT NULL,
-    slug VARCHAR(255) NOT NULL,
-    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
-);
-
-CREATE TABLE campaign_images (
-    id SERIAL PRIMARY KEY,
-    campaign_id INT NOT NULL,
-    file_name VARCHAR(255) NOT NULL,
-    is_primary SMALLINT NOT NULL,
-    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
-);
\ No newline at end of file
@@ -0,0 +1,350 @@
+package docs
+
+import (
+	"auth-gorm-echo/helper"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Auth menentukan cara endpoint diautentikasi
+type Auth int
+
+const (
+	AuthNone Auth = iota
+	AuthRequired
+	// AuthOptional: tanpa token tetap boleh, dengan token response menyesuaikan user
+	AuthOptional
+)
+
+// Operation mendeskripsikan satu route di main.go
+type Operation struct {
+	Method  string
+	Path    string
+	Summary string
+	Tag     string
+	Auth    Auth
+	// Body adalah struct input JSON (tag json + validate)
+	Body interface{}
+	// Form adalah struct input multipart (tag form + validate)
+	Form interface{}
+	// File adalah nama field file pada multipart form
+	File string
+	// Params adalah struct dengan tag param, kalau kosong path param dianggap string
+	Params interface{}
+	Query  []QueryParam
+	// Conditional: mendukung ETag / If-None-Match dan bisa membalas 304
+	Conditional bool
+	// RateLimited: dibatasi ratelimit.Policy dan bisa membalas 429
+	RateLimited bool
+	// ContentType diisi untuk response yang tidak memakai envelope helper.Response
+	ContentType string
+	// Responses berisi satu atau beberapa kemungkinan isi data pada envelope
+	Responses []interface{}
+}
+
+type QueryParam struct {
+	Name        string
+	Type        string
+	Description string
+}
+
+type Document struct {
+	OpenAPI    string                          `json:"openapi"`
+	Info       Info                            `json:"info"`
+	Servers    []Server                        `json:"servers,omitempty"`
+	Tags       []Tag                           `json:"tags,omitempty"`
+	Paths      map[string]map[string]*PathItem `json:"paths"`
+	Components Components                      `json:"components"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+type Server struct {
+	URL string `json:"url"`
+}
+
+type Tag struct {
+	Name string `json:"name"`
+}
+
+type PathItem struct {
+	Summary     string                `json:"summary"`
+	OperationID string                `json:"operationId"`
+	Tags        []string              `json:"tags,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Required    bool    `json:"required"`
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                  `json:"required"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes"`
+}
+
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+	In           string `json:"in,omitempty"`
+	Name         string `json:"name,omitempty"`
+	Description  string `json:"description,omitempty"`
+}
+
+type generator struct {
+	schemas map[string]*Schema
+}
+
+// Build membuat dokumen OpenAPI dari daftar operation. scopes adalah scope
+// API key per route ("METHOD /path"), route yang tidak ada di sini hanya
+// bisa diakses dengan JWT.
+func Build(operations []Operation, scopes map[string]string) *Document {
+	g := &generator{schemas: map[string]*Schema{}}
+
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info: Info{
+			Title:       "Crowdfunding API",
+			Version:     "1.0.0",
+			Description: "Semua response dibungkus envelope {meta, data}. Bahasa pesan mengikuti header Accept-Language (id atau en).",
+		},
+		Servers: []Server{{URL: "/"}},
+		Paths:   map[string]map[string]*PathItem{},
+		Components: Components{
+			Schemas: g.schemas,
+			SecuritySchemes: map[string]*SecurityScheme{
+				"bearerAuth": {
+					Type:         "http",
+					Scheme:       "bearer",
+					BearerFormat: "JWT",
+				},
+				"apiKeyAuth": {
+					Type:        "apiKey",
+					In:          "header",
+					Name:        "Authorization",
+					Description: "Format: ApiKey cf_<key>. Hanya berlaku untuk route yang mencantumkan scope.",
+				},
+			},
+		},
+	}
+
+	g.schemaFor(reflect.TypeOf(helper.Meta{}))
+
+	tags := map[string]bool{}
+
+	for _, operation := range operations {
+		path := openAPIPath(operation.Path)
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = map[string]*PathItem{}
+		}
+
+		doc.Paths[path][strings.ToLower(operation.Method)] = g.pathItem(operation, scopes[operation.Method+" "+operation.Path])
+
+		if operation.Tag != "" && !tags[operation.Tag] {
+			tags[operation.Tag] = true
+			doc.Tags = append(doc.Tags, Tag{Name: operation.Tag})
+		}
+	}
+
+	return doc
+}
+
+func (g *generator) pathItem(operation Operation, scope string) *PathItem {
+	item := &PathItem{
+		Summary:     operation.Summary,
+		OperationID: operationID(operation),
+		Responses:   map[string]*Response{},
+	}
+
+	if operation.Tag != "" {
+		item.Tags = []string{operation.Tag}
+	}
+
+	item.Parameters = g.pathParams(operation)
+	for _, query := range operation.Query {
+		item.Parameters = append(item.Parameters, Parameter{
+			Name:        query.Name,
+			In:          "query",
+			Description: query.Description,
+			Schema:      &Schema{Type: query.Type},
+		})
+	}
+
+	if operation.Body != nil {
+		item.RequestBody = &RequestBody{
+			Required: true,
+			Content: map[string]*MediaType{
+				echo.MIMEApplicationJSON: {Schema: g.schemaFor(reflect.TypeOf(operation.Body))},
+			},
+		}
+	}
+
+	if operation.Form != nil || operation.File != "" {
+		form := &Schema{Type: "object", Properties: map[string]*Schema{}}
+		if operation.Form != nil {
+			form = g.objectSchema(reflect.TypeOf(operation.Form), "form")
+		}
+
+		if operation.File != "" {
+			form.Properties[operation.File] = &Schema{Type: "string", Format: "binary"}
+			form.Required = append(form.Required, operation.File)
+		}
+
+		item.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]*MediaType{"multipart/form-data": {Schema: form}},
+		}
+	}
+
+	// security kosong berarti tidak butuh autentikasi, {} berarti boleh anonim
+	switch operation.Auth {
+	case AuthRequired, AuthOptional:
+		item.Security = []map[string][]string{{"bearerAuth": {}}}
+		if scope != "" {
+			item.Security = append(item.Security, map[string][]string{"apiKeyAuth": {scope}})
+			item.Summary += " (API key scope: " + scope + ")"
+		}
+
+		if operation.Auth == AuthOptional {
+			item.Security = append(item.Security, map[string][]string{})
+		}
+
+		item.Responses["401"] = &Response{Description: "Unauthorized", Content: g.envelope(nil)}
+	}
+
+	item.Responses["200"] = &Response{Description: "OK", Content: g.envelope(operation.Responses)}
+	if operation.ContentType != "" {
+		raw := &Schema{Type: "string"}
+		if operation.ContentType == echo.MIMEApplicationJSON {
+			raw = &Schema{Type: "object"}
+		}
+
+		item.Responses["200"].Content = map[string]*MediaType{operation.ContentType: {Schema: raw}}
+	}
+	if operation.Conditional {
+		item.Responses["304"] = &Response{Description: "Not Modified, ETag atau Last-Modified masih sama"}
+	}
+	if operation.RateLimited {
+		item.Responses["429"] = &Response{Description: "Too Many Requests, lihat header Retry-After dan X-RateLimit-*", Content: g.envelope(nil)}
+	}
+	if operation.Body != nil || operation.Form != nil {
+		item.Responses["422"] = &Response{Description: "Validation failed", Content: g.envelope([]interface{}{validationErrors{}})}
+	}
+	item.Responses["default"] = &Response{Description: "Error", Content: g.envelope(nil)}
+
+	return item
+}
+
+// envelope membungkus data dengan helper.Response
+func (g *generator) envelope(responses []interface{}) map[string]*MediaType {
+	data := &Schema{Nullable: true}
+
+	var schemas []*Schema
+	for _, response := range responses {
+		schemas = append(schemas, g.schemaFor(reflect.TypeOf(response)))
+	}
+
+	if len(schemas) == 1 {
+		data = schemas[0]
+	} else if len(schemas) > 1 {
+		data = &Schema{OneOf: schemas}
+	}
+
+	schema := &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"meta": {Ref: "#/components/schemas/Meta"},
+			"data": data,
+		},
+		Required: []string{"meta", "data"},
+	}
+
+	return map[string]*MediaType{echo.MIMEApplicationJSON: {Schema: schema}}
+}
+
+func (g *generator) pathParams(operation Operation) []Parameter {
+	types := map[string]*Schema{}
+	if operation.Params != nil {
+		t := reflect.TypeOf(operation.Params)
+		for i := 0; i < t.NumField(); i++ {
+			if name := t.Field(i).Tag.Get("param"); name != "" {
+				types[name] = g.schemaFor(t.Field(i).Type)
+			}
+		}
+	}
+
+	var params []Parameter
+	for _, segment := range strings.Split(operation.Path, "/") {
+		if !strings.HasPrefix(segment, ":") {
+			continue
+		}
+
+		name := strings.TrimPrefix(segment, ":")
+		schema, ok := types[name]
+		if !ok {
+			schema = &Schema{Type: "string"}
+		}
+
+		params = append(params, Parameter{Name: name, In: "path", Required: true, Schema: schema})
+	}
+
+	return params
+}
+
+// openAPIPath mengubah /campaigns/:id menjadi /campaigns/{id}
+func openAPIPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			segments[i] = "{" + strings.TrimPrefix(segment, ":") + "}"
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
+
+// operationID contoh: post_api_v1_campaigns_id
+func operationID(operation Operation) string {
+	replacer := strings.NewReplacer("/", "_", ":", "", "-", "_")
+	return strings.ToLower(operation.Method) + replacer.Replace(operation.Path)
+}
+
+// Routes mengembalikan "METHOD /path" dari semua operation, terurut
+func Routes(operations []Operation) []string {
+	routes := []string{}
+	for _, operation := range operations {
+		routes = append(routes, operation.Method+" "+operation.Path)
+	}
+
+	sort.Strings(routes)
+	return routes
+}
@@ -0,0 +1,354 @@
+package docs
+
+import (
+	"auth-gorm-echo/apikey"
+	"auth-gorm-echo/campaign"
+	"auth-gorm-echo/handler"
+	"auth-gorm-echo/jobs"
+	"auth-gorm-echo/pledge"
+	"auth-gorm-echo/user"
+	"auth-gorm-echo/webhook"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// struct berikut hanya untuk dokumentasi, handler membalas dengan echo.Map
+
+type EmailAvailability struct {
+	IsAvailable bool `json:"is_available"`
+}
+
+type UploadResult struct {
+	IsUploaded bool `json:"is_uploaded"`
+}
+
+type RecoveryCodes struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type HealthStatus struct {
+	Status string `json:"status"`
+}
+
+type ReadinessStatus struct {
+	Status string                              `json:"status"`
+	Checks map[string]handler.DependencyStatus `json:"checks"`
+}
+
+type validationErrors struct {
+	Errors map[string]string `json:"errors"`
+}
+
+// Operations adalah daftar semua route di main.go. Setiap route baru harus
+// ditambahkan di sini, kalau tidak `server docs check` akan gagal.
+var Operations = []Operation{
+	{
+		Method:      http.MethodGet,
+		Path:        "/metrics",
+		Summary:     "Prometheus metrics",
+		Tag:         "ops",
+		ContentType: "text/plain",
+	},
+	{
+		Method:    http.MethodGet,
+		Path:      "/healthz",
+		Summary:   "Liveness probe",
+		Tag:       "ops",
+		Responses: []interface{}{HealthStatus{}},
+	},
+	{
+		Method:    http.MethodGet,
+		Path:      "/readyz",
+		Summary:   "Readiness probe, mengecek postgres dan redis",
+		Tag:       "ops",
+		Responses: []interface{}{ReadinessStatus{}},
+	},
+	{
+		Method:      http.MethodGet,
+		Path:        "/api/v1/openapi.json",
+		Summary:     "Dokumen OpenAPI ini",
+		Tag:         "ops",
+		ContentType: echo.MIMEApplicationJSON,
+	},
+	{
+		Method:      http.MethodPost,
+		Path:        "/api/v1/users",
+		Summary:     "Register user baru",
+		Tag:         "users",
+		Body:        user.RegisterUserInput{},
+		Responses:   []interface{}{user.UserFormatter{}},
+		RateLimited: true,
+	},
+	{
+		Method:      http.MethodPost,
+		Path:        "/api/v1/sessions",
+		Summary:     "Login dengan email dan password, user dengan 2FA mendapat mfa_token",
+		Tag:         "sessions",
+		Body:        user.LoginInput{},
+		Responses:   []interface{}{user.UserFormatter{}, user.MFAChallengeFormatter{}},
+		RateLimited: true,
+	},
+	{
+		Method:      http.MethodPost,
+		Path:        "/api/v1/sessions/2fa",
+		Summary:     "Menyelesaikan login dengan kode 2FA atau recovery code",
+		Tag:         "sessions",
+		Body:        user.TwoFactorLoginInput{},
+		Responses:   []interface{}{user.UserFormatter{}},
+		RateLimited: true,
+	},
+	{
+		Method:      http.MethodPost,
+		Path:        "/api/v1/sessions/oauth/:provider",
+		Summary:     "Login dengan authorization code OAuth (PKCE)",
+		Tag:         "sessions",
+		Body:        user.OAuthLoginInput{},
+		Responses:   []interface{}{user.UserFormatter{}, user.MFAChallengeFormatter{}},
+		RateLimited: true,
+	},
+	{
+		Method:      http.MethodPost,
+		Path:        "/api/v1/email_checkers",
+		Summary:     "Cek apakah email sudah terdaftar",
+		Tag:         "users",
+		Body:        user.CheckEmailInput{},
+		Responses:   []interface{}{EmailAvailability{}},
+		RateLimited: true,
+	},
+	{
+		Method:      http.MethodGet,
+		Path:        "/api/v1/campaigns",
+		Summary:     "Daftar campaign",
+		Tag:         "campaigns",
+		Auth:        AuthOptional,
+		Conditional: true,
+		Query: []QueryParam{
+			{Name: "user_id", Type: "integer", Description: "hanya campaign milik user ini"},
+		},
+		Responses: []interface{}{[]campaign.CampaignFormatter{}},
+	},
+	{
+		Method:      http.MethodGet,
+		Path:        "/api/v1/campaigns/:id",
+		Summary:     "Detail campaign",
+		Tag:         "campaigns",
+		Auth:        AuthOptional,
+		Conditional: true,
+		Params:      campaign.GetCampaignDetailInput{},
+		Responses:   []interface{}{campaign.CampaignDetailFormatter{}},
+	},
+	{
+		Method:      http.MethodGet,
+		Path:        "/api/v1/campaigns/:id/stream",
+		Summary:     "Server-Sent Events progress pendanaan, event \"progress\" berisi campaign.Progress dan dikirim setiap transaksi settle",
+		Tag:         "campaigns",
+		Auth:        AuthNone,
+		Params:      campaign.GetCampaignDetailInput{},
+		ContentType: "text/event-stream",
+		RateLimited: true,
+	},
+	{
+		Method:      http.MethodGet,
+		Path:        "/api/v1/campaigns/:id/ws",
+		Summary:     "WebSocket progress pendanaan, pesan {\"type\": \"progress\"|\"ping\", \"data\": campaign.Progress}",
+		Tag:         "campaigns",
+		Auth:        AuthNone,
+		Params:      campaign.GetCampaignDetailInput{},
+		ContentType: echo.MIMEApplicationJSON,
+		RateLimited: true,
+	},
+	{
+		Method:    http.MethodGet,
+		Path:      "/api/v1/users/fetch",
+		Summary:   "User yang sedang login",
+		Tag:       "users",
+		Auth:      AuthRequired,
+		Responses: []interface{}{user.UserFormatter{}},
+	},
+	{
+		Method:    http.MethodPost,
+		Path:      "/api/v1/avatars",
+		Summary:   "Upload avatar (maksimal 6 MB)",
+		Tag:       "users",
+		Auth:      AuthRequired,
+		File:      "avatar",
+		Responses: []interface{}{UploadResult{}},
+	},
+	{
+		Method:    http.MethodPost,
+		Path:      "/api/v1/users/me/2fa",
+		Summary:   "Mulai setup 2FA (TOTP)",
+		Tag:       "2fa",
+		Auth:      AuthRequired,
+		Responses: []interface{}{user.TwoFactorSetupFormatter{}},
+	},
+	{
+		Method:    http.MethodPost,
+		Path:      "/api/v1/users/me/2fa/confirm",
+		Summary:   "Aktifkan 2FA dengan kode dari authenticator",
+		Tag:       "2fa",
+		Auth:      AuthRequired,
+		Body:      user.TwoFactorCodeInput{},
+		Responses: []interface{}{RecoveryCodes{}},
+	},
+	{
+		Method:  http.MethodDelete,
+		Path:    "/api/v1/users/me/2fa",
+		Summary: "Nonaktifkan 2FA",
+		Tag:     "2fa",
+		Auth:    AuthRequired,
+		Body:    user.TwoFactorCodeInput{},
+	},
+	{
+		Method:    http.MethodPost,
+		Path:      "/api/v1/users/me/api_keys",
+		Summary:   "Buat API key, key plain text hanya ditampilkan sekali",
+		Tag:       "api_keys",
+		Auth:      AuthRequired,
+		Body:      apikey.CreateAPIKeyInput{},
+		Responses: []interface{}{apikey.CreatedAPIKeyFormatter{}},
+	},
+	{
+		Method:    http.MethodGet,
+		Path:      "/api/v1/users/me/api_keys",
+		Summary:   "Daftar API key milik user",
+		Tag:       "api_keys",
+		Auth:      AuthRequired,
+		Responses: []interface{}{[]apikey.APIKeyFormatter{}},
+	},
+	{
+		Method:  http.MethodDelete,
+		Path:    "/api/v1/users/me/api_keys/:id",
+		Summary: "Cabut API key",
+		Tag:     "api_keys",
+		Auth:    AuthRequired,
+		Params:  apikey.DeleteAPIKeyInput{},
+	},
+	{
+		Method:    http.MethodPost,
+		Path:      "/api/v1/users/me/webhooks",
+		Summary:   "Daftarkan endpoint webhook, secret untuk verifikasi tanda tangan hanya ditampilkan sekali",
+		Tag:       "webhooks",
+		Auth:      AuthRequired,
+		Body:      webhook.CreateEndpointInput{},
+		Responses: []interface{}{webhook.CreatedEndpointFormatter{}},
+	},
+	{
+		Method:    http.MethodGet,
+		Path:      "/api/v1/users/me/webhooks",
+		Summary:   "Daftar endpoint webhook milik user",
+		Tag:       "webhooks",
+		Auth:      AuthRequired,
+		Responses: []interface{}{[]webhook.EndpointFormatter{}},
+	},
+	{
+		Method:  http.MethodDelete,
+		Path:    "/api/v1/users/me/webhooks/:id",
+		Summary: "Hapus endpoint webhook",
+		Tag:     "webhooks",
+		Auth:    AuthRequired,
+		Params:  webhook.EndpointInput{},
+	},
+	{
+		Method:  http.MethodPost,
+		Path:    "/api/v1/users/me/webhooks/:id/test",
+		Summary: "Kirim event webhook.test ke endpoint",
+		Tag:     "webhooks",
+		Auth:    AuthRequired,
+		Params:  webhook.EndpointInput{},
+	},
+	{
+		Method:    http.MethodGet,
+		Path:      "/api/v1/users/me/webhooks/:id/deliveries",
+		Summary:   "Log pengiriman webhook terbaru beserta status code response",
+		Tag:       "webhooks",
+		Auth:      AuthRequired,
+		Params:    webhook.EndpointInput{},
+		Responses: []interface{}{[]webhook.DeliveryFormatter{}},
+	},
+	{
+		Method:      http.MethodPost,
+		Path:        "/api/v1/campaigns",
+		Summary:     "Buat campaign",
+		Tag:         "campaigns",
+		Auth:        AuthRequired,
+		Body:        campaign.CreateCampaignInput{},
+		Responses:   []interface{}{campaign.CampaignFormatter{}},
+		RateLimited: true,
+	},
+	{
+		Method:    http.MethodPost,
+		Path:      "/api/v1/campaign-images",
+		Summary:   "Upload gambar campaign (maksimal 11 MB)",
+		Tag:       "campaigns",
+		Auth:      AuthRequired,
+		Form:      campaign.CreateCampaignImageInput{},
+		File:      "file",
+		Responses: []interface{}{UploadResult{}},
+	},
+	{
+		Method:    http.MethodPost,
+		Path:      "/api/v1/campaigns/:id/pledges",
+		Summary:   "Buat pledge, dana campaign bertambah setelah pembayaran dikonfirmasi",
+		Tag:       "pledges",
+		Auth:      AuthRequired,
+		Params:    pledge.CreatePledgeInput{},
+		Body:      pledge.CreatePledgeInput{},
+		Responses: []interface{}{pledge.PledgeFormatter{}},
+	},
+	{
+		Method:    http.MethodGet,
+		Path:      "/api/v1/campaigns/:id/pledges",
+		Summary:   "Daftar pledge campaign (pemilik campaign)",
+		Tag:       "pledges",
+		Auth:      AuthRequired,
+		Params:    pledge.CampaignPledgesInput{},
+		Responses: []interface{}{[]pledge.PledgeFormatter{}},
+	},
+	{
+		Method:    http.MethodGet,
+		Path:      "/api/v1/admin/jobs",
+		Summary:   "Jumlah job per status antrian (admin)",
+		Tag:       "admin",
+		Auth:      AuthRequired,
+		Responses: []interface{}{jobs.Stats{}},
+	},
+	{
+		Method:  http.MethodGet,
+		Path:    "/api/v1/admin/jobs/dead",
+		Summary: "Job di dead letter queue, paling baru lebih dulu (admin)",
+		Tag:     "admin",
+		Auth:    AuthRequired,
+		Query: []QueryParam{
+			{Name: "limit", Type: "integer", Description: "1-200, default 50"},
+		},
+		Responses: []interface{}{[]jobs.Job{}},
+	},
+	{
+		Method:    http.MethodPost,
+		Path:      "/api/v1/admin/jobs/dead/:id/retry",
+		Summary:   "Jalankan ulang job dari dead letter queue (admin)",
+		Tag:       "admin",
+		Auth:      AuthRequired,
+		Responses: []interface{}{jobs.Job{}},
+	},
+	{
+		Method:    http.MethodPost,
+		Path:      "/api/v1/admin/pledges/:id/paid",
+		Summary:   "Tandai pledge pending sebagai dibayar, dana campaign bertambah (admin)",
+		Tag:       "admin",
+		Auth:      AuthRequired,
+		Params:    pledge.PledgeInput{},
+		Responses: []interface{}{pledge.PledgeFormatter{}},
+	},
+	{
+		Method:    http.MethodPost,
+		Path:      "/api/v1/admin/pledges/:id/refund",
+		Summary:   "Refund pledge yang sudah dibayar, dana campaign berkurang (admin)",
+		Tag:       "admin",
+		Auth:      AuthRequired,
+		Params:    pledge.PledgeInput{},
+		Responses: []interface{}{pledge.PledgeFormatter{}},
+	},
+}
@@ -0,0 +1,179 @@
+package docs
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schema adalah subset JSON Schema yang dipakai OpenAPI 3.0
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Minimum              *int               `json:"minimum,omitempty"`
+	Maximum              *int               `json:"maximum,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+	Description          string             `json:"description,omitempty"`
+}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// schemaFor membuat schema dari tipe Go. Struct didaftarkan di
+// components.schemas dan direferensikan lewat $ref.
+func (g *generator) schemaFor(t reflect.Type) *Schema {
+	if t.Kind() == reflect.Ptr {
+		schema := g.schemaFor(t.Elem())
+		if schema.Ref != "" {
+			// $ref tidak boleh punya sibling di OpenAPI 3.0
+			return &Schema{OneOf: []*Schema{schema}, Nullable: true}
+		}
+
+		schema.Nullable = true
+		return schema
+	}
+
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+
+	// isi bebas, contoh payload job
+	if t == rawMessageType {
+		return &Schema{Description: "Any JSON value"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: g.schemaFor(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schemaFor(t.Elem())}
+	case reflect.Struct:
+		name := t.Name()
+		if _, ok := g.schemas[name]; !ok {
+			// daftarkan dulu supaya struct yang saling mereferensikan tidak berulang
+			g.schemas[name] = &Schema{}
+			*g.schemas[name] = *g.objectSchema(t, "json")
+		}
+
+		return &Schema{Ref: "#/components/schemas/" + name}
+	}
+
+	return &Schema{}
+}
+
+// objectSchema membuat schema object inline dari field struct yang punya tag
+// tagName (json atau form), constraint diambil dari tag validate
+func (g *generator) objectSchema(t reflect.Type, tagName string) *Schema {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		// struct embedded, contoh CreatedAPIKeyFormatter
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			embedded := g.objectSchema(field.Type, tagName)
+			for name, property := range embedded.Properties {
+				schema.Properties[name] = property
+			}
+			schema.Required = append(schema.Required, embedded.Required...)
+			continue
+		}
+
+		name := strings.SplitN(field.Tag.Get(tagName), ",", 2)[0]
+		if name == "" || name == "-" || !field.IsExported() {
+			continue
+		}
+
+		property := g.schemaFor(field.Type)
+		if applyConstraints(property, field.Tag.Get("validate")) {
+			schema.Required = append(schema.Required, name)
+		}
+
+		schema.Properties[name] = property
+	}
+
+	return schema
+}
+
+// applyConstraints menerjemahkan tag validate ke constraint schema,
+// mengembalikan true kalau field wajib diisi
+func applyConstraints(schema *Schema, tag string) bool {
+	required := false
+	target := schema
+
+	for _, rule := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+
+		switch name {
+		case "required":
+			required = true
+		case "dive":
+			// rule setelah dive berlaku untuk setiap item
+			if target.Items != nil {
+				target = target.Items
+			}
+		case "email":
+			target.Format = "email"
+		case "url":
+			target.Format = "uri"
+		case "oneof":
+			target.Enum = strings.Fields(param)
+		case "min", "max":
+			value, err := strconv.Atoi(param)
+			if err != nil {
+				continue
+			}
+
+			setBound(target, name == "min", value)
+		}
+	}
+
+	return required
+}
+
+func setBound(schema *Schema, isMin bool, value int) {
+	switch schema.Type {
+	case "string":
+		if isMin {
+			schema.MinLength = &value
+		} else {
+			schema.MaxLength = &value
+		}
+	case "array":
+		if isMin {
+			schema.MinItems = &value
+		} else {
+			schema.MaxItems = &value
+		}
+	default:
+		if isMin {
+			schema.Minimum = &value
+		} else {
+			schema.Maximum = &value
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package docs
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+const redocPage = `<!DOCTYPE html>
+<html>
+  <head>
+    <title>Crowdfunding API</title>
+    <meta charset="utf-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1">
+  </head>
+  <body>
+    <redoc spec-url="/api/v1/openapi.json"></redoc>
+    <script src="https://cdn.redoc.ly/redoc/latest/bundles/redoc.standalone.js"></script>
+  </body>
+</html>
+`
+
+// SpecHandler mengirim dokumen OpenAPI apa adanya, tanpa envelope helper.Response
+func SpecHandler(doc *Document) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return c.JSONPretty(http.StatusOK, doc, "  ")
+	}
+}
+
+// UIHandler menampilkan Redoc yang membaca /api/v1/openapi.json
+func UIHandler(c echo.Context) error {
+	return c.HTML(http.StatusOK, redocPage)
+}
@@ -0,0 +1,61 @@
+package docs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// route yang sengaja tidak masuk dokumen
+var undocumented = []string{"/docs", "/images"}
+
+// Verify membandingkan route yang terdaftar di router dengan Operations,
+// error berisi route yang belum didokumentasikan atau sudah tidak ada
+func Verify(routes []*echo.Route, operations []Operation) error {
+	registered := map[string]bool{}
+	for _, route := range routes {
+		// Group.Use mendaftarkan route not found internal milik echo
+		if route.Method == echo.RouteNotFound || isUndocumented(route.Path) {
+			continue
+		}
+
+		registered[route.Method+" "+route.Path] = true
+	}
+
+	documented := map[string]bool{}
+	for _, route := range Routes(operations) {
+		documented[route] = true
+	}
+
+	var problems []string
+	for route := range registered {
+		if !documented[route] {
+			problems = append(problems, "undocumented route "+route)
+		}
+	}
+
+	for route := range documented {
+		if !registered[route] {
+			problems = append(problems, "documented route not registered "+route)
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+
+	sort.Strings(problems)
+	return fmt.Errorf("openapi spec out of sync with router:\n  %s", strings.Join(problems, "\n  "))
+}
+
+func isUndocumented(path string) bool {
+	for _, prefix := range undocumented {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
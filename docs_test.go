@@ -0,0 +1,18 @@
+package main
+
+import (
+	"auth-gorm-echo/docs"
+	"auth-gorm-echo/logging"
+	"testing"
+)
+
+// gagal kalau ada route yang belum ada di docs.Operations atau sebaliknya,
+// sama dengan `server docs check`
+func TestOpenAPIMatchesRoutes(t *testing.T) {
+	router, _ := newRouter(&app{logger: logging.New()})
+
+	err := docs.Verify(router.Routes(), docs.Operations)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,74 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"strings"
+)
+
+// prefix versi supaya key / algoritma bisa diganti tanpa merusak data lama
+const prefix = "v1:"
+
+var ErrInvalidCiphertext = errors.New("encryption: invalid ciphertext")
+
+// Cipher mengenkripsi data yang harus bisa dibaca lagi oleh server (contohnya
+// secret TOTP) dengan AES-256-GCM memakai APP_KEY
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+func NewCipher(key []byte) (*Cipher, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Cipher{aead}, nil
+}
+
+// Encrypt mengembalikan "v1:" + base64(nonce + ciphertext)
+func (c *Cipher) Encrypt(plaintext string) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	_, err := rand.Read(nonce)
+	if err != nil {
+		return "", err
+	}
+
+	sealed := c.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+
+	return prefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt mengembalikan value apa adanya kalau belum terenkripsi, yaitu data
+// yang disimpan sebelum enkripsi diterapkan
+func (c *Cipher) Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, prefix))
+	if err != nil || len(sealed) < c.aead.NonceSize() {
+		return "", ErrInvalidCiphertext
+	}
+
+	nonce, ciphertext := sealed[:c.aead.NonceSize()], sealed[c.aead.NonceSize():]
+
+	plaintext, err := c.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", ErrInvalidCiphertext
+	}
+
+	return string(plaintext), nil
+}
+
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
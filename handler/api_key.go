@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"auth-gorm-echo/apikey"
+	"auth-gorm-echo/helper"
+	"auth-gorm-echo/user"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type apiKeyHandler struct {
+	service apikey.Service
+}
+
+func NewAPIKeyHandler(service apikey.Service) *apiKeyHandler {
+	return &apiKeyHandler{service}
+}
+
+func (h *apiKeyHandler) CreateAPIKey(c echo.Context) error {
+	var input apikey.CreateAPIKeyInput
+
+	err := c.Bind(&input)
+	if err != nil {
+		return err
+	}
+
+	err = c.Validate(&input)
+	if err != nil {
+		return err
+	}
+
+	currentUser := c.Get("currentUser").(user.User)
+	input.User = currentUser
+
+	newAPIKey, rawKey, err := h.service.CreateAPIKey(c.Request().Context(), input)
+	if err != nil {
+		return err
+	}
+
+	response := helper.APIResponse("api_key.created", http.StatusOK, "success", apikey.FormatCreatedAPIKey(newAPIKey, rawKey))
+	return c.JSON(http.StatusOK, response)
+}
+
+func (h *apiKeyHandler) GetAPIKeys(c echo.Context) error {
+	currentUser := c.Get("currentUser").(user.User)
+
+	apiKeys, err := h.service.GetAPIKeys(c.Request().Context(), currentUser.ID)
+	if err != nil {
+		return err
+	}
+
+	response := helper.APIResponse("api_key.list", http.StatusOK, "success", apikey.FormatAPIKeys(apiKeys))
+	return c.JSON(http.StatusOK, response)
+}
+
+func (h *apiKeyHandler) DeleteAPIKey(c echo.Context) error {
+	var input apikey.DeleteAPIKeyInput
+
+	err := c.Bind(&input)
+	if err != nil {
+		return apikey.ErrAPIKeyNotFound
+	}
+
+	currentUser := c.Get("currentUser").(user.User)
+	input.User = currentUser
+
+	err = h.service.DeleteAPIKey(c.Request().Context(), input)
+	if err != nil {
+		return err
+	}
+
+	response := helper.APIResponse("api_key.deleted", http.StatusOK, "success", nil)
+	return c.JSON(http.StatusOK, response)
+}
@@ -3,9 +3,13 @@ package handler
 import (
 	"auth-gorm-echo/campaign"
 	"auth-gorm-echo/helper"
+	"auth-gorm-echo/upload"
 	"auth-gorm-echo/user"
+	"fmt"
+	"log/slog"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/labstack/echo/v4"
 )
@@ -18,23 +22,47 @@ import (
 
 type campaignHandler struct {
 	service campaign.Service
+	uploadService upload.Service
+	logger *slog.Logger
 }
 
-func NewCampaignHandler(service campaign.Service) *campaignHandler {
-	return &campaignHandler{service}
+func NewCampaignHandler(service campaign.Service, uploadService upload.Service, logger *slog.Logger) *campaignHandler {
+	return &campaignHandler{service, uploadService, logger}
 }
 
 func (h *campaignHandler) GetCampaigns(c echo.Context) error {
 
 	userID, _ := strconv.Atoi(c.QueryParam("user_id"))
 
-	campaigns, err := h.service.GetCampaigns(userID)
+	campaigns, err := h.service.GetCampaigns(c.Request().Context(), userID)
 	if err != nil {
-		response := helper.APIResponse("Error to get campaigns", http.StatusBadRequest, "error", nil)
-		return c.JSON(http.StatusBadRequest, response)
+		return err
 	}
 
-	response := helper.APIResponse("List of campaigns", http.StatusOK, "success", campaign.FormatCampaigns(campaigns))
+	var lastModified time.Time
+	versions := []string{}
+	for _, item := range campaigns {
+		if item.UpdatedAt.After(lastModified) {
+			lastModified = item.UpdatedAt
+		}
+
+		versions = append(versions, fmt.Sprintf("%d:%d", item.ID, item.UpdatedAt.UnixNano()))
+	}
+
+	if checkNotModified(c, lastModified, versions...) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	campaignsFormatter := campaign.FormatCampaigns(campaigns)
+
+	// currentUser hanya ada kalau request membawa token (optional auth)
+	if currentUser, ok := c.Get("currentUser").(user.User); ok {
+		for i := range campaignsFormatter {
+			campaignsFormatter[i].IsOwner = campaignsFormatter[i].UserID == currentUser.ID
+		}
+	}
+
+	response := helper.APIResponse("campaign.list", http.StatusOK, "success", campaignsFormatter)
 	return c.JSON(http.StatusOK, response)
 }
 
@@ -46,19 +74,35 @@ func (h *campaignHandler) GetCampaign(c echo.Context) error {
 
 	var input campaign.GetCampaignDetailInput
 
+	// id yang bukan angka tidak mungkin ada
 	err := c.Bind(&input)
 	if err != nil {
-		response := helper.APIResponse("Failed to get detail of campaign", http.StatusBadRequest, "error", nil)
-		return c.JSON(http.StatusBadRequest, response)
+		return campaign.ErrCampaignNotFound
 	}
 
-	campaignDetail, err := h.service.GetCampaignByID(input)
+	campaignDetail, err := h.service.GetCampaignByID(c.Request().Context(), input)
 	if err != nil {
-		response := helper.APIResponse("Failed to get detail of campaign", http.StatusBadRequest, "error", nil)
-		return c.JSON(http.StatusBadRequest, response)
+		return err
+	}
+
+	// nama dan avatar pemilik ikut tampil di detail
+	lastModified := campaignDetail.UpdatedAt
+	if campaignDetail.User.UpdatedAt.After(lastModified) {
+		lastModified = campaignDetail.User.UpdatedAt
+	}
+
+	version := fmt.Sprintf("%d:%d:%d", campaignDetail.ID, campaignDetail.UpdatedAt.UnixNano(), campaignDetail.User.UpdatedAt.UnixNano())
+	if checkNotModified(c, lastModified, version) {
+		return c.NoContent(http.StatusNotModified)
 	}
 
-	response := helper.APIResponse("Campaign detail", http.StatusOK, "success", campaign.FormatCampaignDetail(campaignDetail))
+	campaignDetailFormatter := campaign.FormatCampaignDetail(campaignDetail)
+
+	if currentUser, ok := c.Get("currentUser").(user.User); ok {
+		campaignDetailFormatter.IsOwner = campaignDetail.UserID == currentUser.ID
+	}
+
+	response := helper.APIResponse("campaign.detail", http.StatusOK, "success", campaignDetailFormatter)
 	return c.JSON(http.StatusOK, response)
 }
 
@@ -71,23 +115,75 @@ func (h *campaignHandler) CreateCampaign(c echo.Context) error {
 	var input campaign.CreateCampaignInput
 
 	err := c.Bind(&input)
-	if err := c.Validate(&input); err != nil {
-		errors := helper.FormatValidationError(err)
-		errorMessage := echo.Map{"errors": errors}
+	if err != nil {
+		return err
+	}
+
+	err = c.Validate(&input)
+	if err != nil {
+		return err
+	}
+
+	currentUser := c.Get("currentUser").(user.User)
+	input.User = currentUser
+
+	newCampaign, err := h.service.CreateCampaign(c.Request().Context(), input)
+	if err != nil {
+		return err
+	}
+
+	response := helper.APIResponse("campaign.created", http.StatusOK, "success", campaign.FormatCampaign(newCampaign))
+	return c.JSON(http.StatusOK, response)
+}
+
+// handler : tangkap input (campaign_id, is_primary, file) ke struct input
+// simpan gambar ke storage lewat upload service
+// service : cek pemilik campaign, kalau primary tandai gambar lain non primary
+// repository : simpan data gambar
+
+func (h *campaignHandler) UploadImage(c echo.Context) error {
+	var input campaign.CreateCampaignImageInput
+
+	err := c.Bind(&input)
+	if err != nil {
+		return err
+	}
 
-		response := helper.APIResponse("Failed to create campaign", http.StatusUnprocessableEntity, "error", errorMessage)
-		return c.JSON(http.StatusUnprocessableEntity, response)
+	err = c.Validate(&input)
+	if err != nil {
+		return err
 	}
 
 	currentUser := c.Get("currentUser").(user.User)
 	input.User = currentUser
 
-	newCampaign, err := h.service.CreateCampaign(input)
+	file, err := c.FormFile("file")
+	if err != nil {
+		return upload.ErrMissingFile
+	}
+
+	src, err := file.Open()
 	if err != nil {
-		response := helper.APIResponse("Failed to create campaign", http.StatusBadRequest, "error", nil)
-		return c.JSON(http.StatusBadRequest, response)
+		return err
+	}
+	defer src.Close()
+
+	image, err := h.uploadService.SaveImage(c.Request().Context(), src)
+	if err != nil {
+		return err
+	}
+
+	_, err = h.service.SaveCampaignImage(c.Request().Context(), input, image.Path)
+	if err != nil {
+		deleteErr := h.uploadService.DeleteImage(c.Request().Context(), image.Path)
+		if deleteErr != nil {
+			h.logger.WarnContext(c.Request().Context(), "failed to delete orphaned campaign image", "key", image.Path, "error", deleteErr)
+		}
+
+		return err
 	}
 
-	response := helper.APIResponse("Campaign has been created", http.StatusOK, "success", campaign.FormatCampaign(newCampaign))
+	data := echo.Map{"is_uploaded": true}
+	response := helper.APIResponse("campaign.image_uploaded", http.StatusOK, "success", data)
 	return c.JSON(http.StatusOK, response)
 }
\ No newline at end of file
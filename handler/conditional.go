@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"auth-gorm-echo/i18n"
+	"auth-gorm-echo/storage"
+	"auth-gorm-echo/user"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// checkNotModified mengisi ETag, Last-Modified dan Cache-Control, lalu
+// mengembalikan true kalau versi yang dimiliki client masih sama sehingga
+// handler cukup membalas 304. versions adalah penanda isi response, contoh
+// id dan updated_at setiap campaign.
+func checkNotModified(c echo.Context, lastModified time.Time, versions ...string) bool {
+	viewerID := 0
+	if currentUser, ok := c.Get("currentUser").(user.User); ok {
+		viewerID = currentUser.ID
+	}
+
+	// is_owner dan bahasa pesan ikut menentukan isi response
+	hash := sha256.New()
+	for _, version := range versions {
+		fmt.Fprintf(hash, "%s\x00", version)
+	}
+	fmt.Fprintf(hash, "%d\x00%s", viewerID, i18n.Locale(c.Request().Context()))
+
+	etag := `W/"` + hex.EncodeToString(hash.Sum(nil)[:16]) + `"`
+
+	header := c.Response().Header()
+	header.Set("ETag", etag)
+	header.Add(echo.HeaderVary, echo.HeaderAuthorization)
+
+	// response untuk user yang login tidak boleh disimpan shared cache / CDN
+	if viewerID != 0 {
+		header.Set(echo.HeaderCacheControl, "private, no-cache")
+	} else {
+		header.Set(echo.HeaderCacheControl, "public, no-cache")
+	}
+
+	if !lastModified.IsZero() {
+		header.Set(echo.HeaderLastModified, lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	// If-None-Match lebih diutamakan daripada If-Modified-Since (RFC 9110)
+	if ifNoneMatch := c.Request().Header.Get("If-None-Match"); ifNoneMatch != "" {
+		return etagMatches(ifNoneMatch, etag)
+	}
+
+	ifModifiedSince := c.Request().Header.Get(echo.HeaderIfModifiedSince)
+	if ifModifiedSince == "" || lastModified.IsZero() {
+		return false
+	}
+
+	since, err := http.ParseTime(ifModifiedSince)
+	if err != nil {
+		return false
+	}
+
+	// Last-Modified hanya presisi detik
+	return !lastModified.Truncate(time.Second).After(since)
+}
+
+// etagMatches membandingkan secara weak, W/"x" sama dengan "x"
+func etagMatches(ifNoneMatch string, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ImmutableCache dipakai untuk /images, lihat storage.ImmutableCacheControl
+func ImmutableCache(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		c.Response().Before(func() {
+			if c.Response().Status == http.StatusOK {
+				c.Response().Header().Set(echo.HeaderCacheControl, storage.ImmutableCacheControl)
+			}
+		})
+
+		return next(c)
+	}
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"auth-gorm-echo/apperror"
+	"auth-gorm-echo/helper"
+	"auth-gorm-echo/i18n"
+	"auth-gorm-echo/validation"
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+var httpErrorCodes = map[int]string{
+	http.StatusBadRequest:            "request.bad_request",
+	http.StatusUnauthorized:          "auth.unauthorized",
+	http.StatusForbidden:             "auth.forbidden",
+	http.StatusNotFound:              "route.not_found",
+	http.StatusMethodNotAllowed:      "route.method_not_allowed",
+	http.StatusRequestEntityTooLarge: "request.too_large",
+	http.StatusUnsupportedMediaType:  "request.unsupported_media_type",
+	http.StatusTooManyRequests:       "request.too_many_requests",
+	http.StatusServiceUnavailable:    "server.unavailable",
+}
+
+// HTTPErrorHandler memetakan error yang dikembalikan handler ke response JSON.
+// Error domain (apperror) memakai status sesuai jenisnya dan kode dari error
+// tersebut, error lain dianggap error server dan detailnya tidak dikirim ke client.
+func HTTPErrorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+
+	status := http.StatusInternalServerError
+	errorCode := "server.internal_error"
+	message := "Internal server error"
+
+	var data interface{}
+	var validationErr *validation.Error
+	var appErr *apperror.Error
+	var httpErr *echo.HTTPError
+
+	switch {
+	case errors.As(err, &validationErr):
+		translator := validation.Translator(i18n.Locale(c.Request().Context()))
+
+		status = http.StatusUnprocessableEntity
+		errorCode = "validation.failed"
+		message = "Validation failed"
+		data = echo.Map{"errors": validationErr.Fields(translator)}
+	case errors.As(err, &appErr):
+		status = statusCode(appErr)
+		errorCode = appErr.Code
+		message = appErr.Message
+	case errors.As(err, &httpErr):
+		status = httpErr.Code
+		errorCode = httpErrorCodes[status]
+		if errorCode == "" {
+			errorCode = "request.error"
+		}
+
+		message = http.StatusText(status)
+		if m, ok := httpErr.Message.(string); ok {
+			message = m
+		}
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		status = http.StatusServiceUnavailable
+		errorCode = httpErrorCodes[status]
+		message = "Request timed out"
+	}
+
+	if c.Request().Method == http.MethodHead {
+		c.NoContent(status)
+		return
+	}
+
+	// error_code sekaligus menjadi key pesan di katalog i18n
+	response := helper.APIErrorResponse(errorCode, status, errorCode, data)
+	if response.Meta.MessageKey == "" {
+		response.Meta.Message = message
+	}
+
+	c.JSON(status, response)
+}
+
+func statusCode(err *apperror.Error) int {
+	switch err.Kind {
+	case apperror.ErrNotFound:
+		return http.StatusNotFound
+	case apperror.ErrForbidden:
+		return http.StatusForbidden
+	case apperror.ErrConflict:
+		return http.StatusConflict
+	case apperror.ErrValidation:
+		return http.StatusUnprocessableEntity
+	case apperror.ErrUnauthorized:
+		return http.StatusUnauthorized
+	case apperror.ErrBadRequest:
+		return http.StatusBadRequest
+	case apperror.ErrTooManyRequests:
+		return http.StatusTooManyRequests
+	}
+
+	return http.StatusInternalServerError
+}
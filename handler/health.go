@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"auth-gorm-echo/helper"
+	"context"
+	"database/sql"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
+)
+
+type healthHandler struct {
+	db       *sql.DB
+	rdb      *redis.Client
+	timeout  time.Duration
+	draining int32
+}
+
+type DependencyStatus struct {
+	Status    string `json:"status"`
+	LatencyMs int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+func NewHealthHandler(db *sql.DB, rdb *redis.Client, timeout time.Duration) *healthHandler {
+	return &healthHandler{db: db, rdb: rdb, timeout: timeout}
+}
+
+// SetDraining dipanggil saat shutdown supaya load balancer berhenti mengirim request
+func (h *healthHandler) SetDraining() {
+	atomic.StoreInt32(&h.draining, 1)
+}
+
+// Liveness: proses masih hidup, tidak mengecek dependency
+func (h *healthHandler) Liveness(c echo.Context) error {
+	response := helper.APIResponse("health.ok", http.StatusOK, "success", echo.Map{"status": "ok"})
+	return c.JSON(http.StatusOK, response)
+}
+
+// Readiness: cek postgres dan redis dengan timeout
+func (h *healthHandler) Readiness(c echo.Context) error {
+	if atomic.LoadInt32(&h.draining) == 1 {
+		response := helper.APIErrorResponse("health.draining", http.StatusServiceUnavailable, "health.draining", echo.Map{"status": "draining"})
+		return c.JSON(http.StatusServiceUnavailable, response)
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request().Context(), h.timeout)
+	defer cancel()
+
+	checks := map[string]func(context.Context) error{
+		"postgres": h.db.PingContext,
+		"redis": func(ctx context.Context) error {
+			return h.rdb.Ping(ctx).Err()
+		},
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	statuses := map[string]DependencyStatus{}
+	ready := true
+
+	for name, check := range checks {
+		wg.Add(1)
+
+		go func(name string, check func(context.Context) error) {
+			defer wg.Done()
+
+			start := time.Now()
+			err := check(ctx)
+
+			status := DependencyStatus{Status: "ok", LatencyMs: time.Since(start).Milliseconds()}
+			if err != nil {
+				status.Status = "error"
+				status.Error = err.Error()
+			}
+
+			mu.Lock()
+			statuses[name] = status
+			if err != nil {
+				ready = false
+			}
+			mu.Unlock()
+		}(name, check)
+	}
+
+	wg.Wait()
+
+	data := echo.Map{"status": "ok", "checks": statuses}
+
+	if !ready {
+		data["status"] = "error"
+
+		response := helper.APIErrorResponse("health.not_ready", http.StatusServiceUnavailable, "health.not_ready", data)
+		return c.JSON(http.StatusServiceUnavailable, response)
+	}
+
+	response := helper.APIResponse("health.ready", http.StatusOK, "success", data)
+	return c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"auth-gorm-echo/helper"
+	"auth-gorm-echo/jobs"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+// jobHandler dipakai admin untuk memantau antrian dan menjalankan ulang job yang gagal
+type jobHandler struct {
+	queue *jobs.Queue
+}
+
+func NewJobHandler(queue *jobs.Queue) *jobHandler {
+	return &jobHandler{queue}
+}
+
+func (h *jobHandler) GetStats(c echo.Context) error {
+	stats, err := h.queue.Stats(c.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	response := helper.APIResponse("job.stats", http.StatusOK, "success", stats)
+	return c.JSON(http.StatusOK, response)
+}
+
+func (h *jobHandler) GetDeadJobs(c echo.Context) error {
+	limit, err := strconv.Atoi(c.QueryParam("limit"))
+	if err != nil || limit < 1 || limit > 200 {
+		limit = 50
+	}
+
+	deadJobs, err := h.queue.DeadJobs(c.Request().Context(), limit)
+	if err != nil {
+		return err
+	}
+
+	response := helper.APIResponse("job.dead_list", http.StatusOK, "success", deadJobs)
+	return c.JSON(http.StatusOK, response)
+}
+
+func (h *jobHandler) RetryDeadJob(c echo.Context) error {
+	job, err := h.queue.RetryDead(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	response := helper.APIResponse("job.retried", http.StatusOK, "success", job)
+	return c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"auth-gorm-echo/campaign"
+	"auth-gorm-echo/helper"
+	"auth-gorm-echo/pledge"
+	"auth-gorm-echo/user"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type pledgeHandler struct {
+	service pledge.Service
+}
+
+func NewPledgeHandler(service pledge.Service) *pledgeHandler {
+	return &pledgeHandler{service}
+}
+
+func (h *pledgeHandler) CreatePledge(c echo.Context) error {
+	var input pledge.CreatePledgeInput
+
+	err := c.Bind(&input)
+	if err != nil {
+		return err
+	}
+
+	err = c.Validate(&input)
+	if err != nil {
+		return err
+	}
+
+	input.User = c.Get("currentUser").(user.User)
+
+	newPledge, err := h.service.CreatePledge(c.Request().Context(), input)
+	if err != nil {
+		return err
+	}
+
+	response := helper.APIResponse("pledge.created", http.StatusOK, "success", pledge.FormatPledge(newPledge))
+	return c.JSON(http.StatusOK, response)
+}
+
+func (h *pledgeHandler) GetCampaignPledges(c echo.Context) error {
+	var input pledge.CampaignPledgesInput
+
+	// id yang bukan angka tidak mungkin ada
+	err := c.Bind(&input)
+	if err != nil {
+		return campaign.ErrCampaignNotFound
+	}
+
+	input.User = c.Get("currentUser").(user.User)
+
+	pledges, err := h.service.GetCampaignPledges(c.Request().Context(), input)
+	if err != nil {
+		return err
+	}
+
+	response := helper.APIResponse("pledge.list", http.StatusOK, "success", pledge.FormatPledges(pledges))
+	return c.JSON(http.StatusOK, response)
+}
+
+// MarkPledgePaid dipakai admin untuk settle pembayaran yang dikonfirmasi manual
+func (h *pledgeHandler) MarkPledgePaid(c echo.Context) error {
+	input, err := bindPledgeInput(c)
+	if err != nil {
+		return err
+	}
+
+	paidPledge, err := h.service.MarkPaid(c.Request().Context(), input)
+	if err != nil {
+		return err
+	}
+
+	response := helper.APIResponse("pledge.paid", http.StatusOK, "success", pledge.FormatPledge(paidPledge))
+	return c.JSON(http.StatusOK, response)
+}
+
+func (h *pledgeHandler) RefundPledge(c echo.Context) error {
+	input, err := bindPledgeInput(c)
+	if err != nil {
+		return err
+	}
+
+	refundedPledge, err := h.service.Refund(c.Request().Context(), input)
+	if err != nil {
+		return err
+	}
+
+	response := helper.APIResponse("pledge.refunded", http.StatusOK, "success", pledge.FormatPledge(refundedPledge))
+	return c.JSON(http.StatusOK, response)
+}
+
+// id yang bukan angka tidak mungkin ada
+func bindPledgeInput(c echo.Context) (pledge.PledgeInput, error) {
+	var input pledge.PledgeInput
+
+	err := c.Bind(&input)
+	if err != nil {
+		return input, pledge.ErrPledgeNotFound
+	}
+
+	return input, nil
+}
@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"auth-gorm-echo/campaign"
+	"auth-gorm-echo/metrics"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/net/websocket"
+)
+
+// heartbeat menjaga koneksi tidak diputus proxy saat tidak ada transaksi
+const (
+	streamHeartbeat    = time.Second * 15
+	streamWriteTimeout = time.Second * 10
+)
+
+// streamMessage adalah format pesan WebSocket, type "progress" atau "ping"
+type streamMessage struct {
+	Type string             `json:"type"`
+	Data *campaign.Progress `json:"data,omitempty"`
+}
+
+type streamHandler struct {
+	service campaign.Service
+	hub     *campaign.ProgressHub
+}
+
+func NewStreamHandler(service campaign.Service, hub *campaign.ProgressHub) *streamHandler {
+	return &streamHandler{service, hub}
+}
+
+// StreamProgress: GET /api/v1/campaigns/:id/stream (Server-Sent Events).
+// Event pertama berisi nilai saat ini, berikutnya dikirim setiap transaksi settle.
+func (h *streamHandler) StreamProgress(c echo.Context) error {
+	updates, snapshot, unsubscribe, err := h.subscribe(c)
+	if err != nil {
+		return err
+	}
+	defer unsubscribe()
+
+	metrics.StreamConnections.WithLabelValues("sse").Inc()
+	defer metrics.StreamConnections.WithLabelValues("sse").Dec()
+
+	response := c.Response()
+	response.Header().Set(echo.HeaderContentType, "text/event-stream")
+	response.Header().Set(echo.HeaderCacheControl, "no-cache")
+	response.Header().Set(echo.HeaderConnection, "keep-alive")
+	// nginx tidak boleh menahan response di buffer
+	response.Header().Set("X-Accel-Buffering", "no")
+	response.WriteHeader(http.StatusOK)
+
+	err = writeEvent(response, snapshot)
+	if err != nil {
+		return nil
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request().Context().Done():
+			return nil
+		case progress, ok := <-updates:
+			if !ok {
+				return nil
+			}
+
+			err = writeEvent(response, progress)
+		case <-heartbeat.C:
+			_, err = fmt.Fprint(response, ": ping\n\n")
+			response.Flush()
+		}
+
+		// client sudah putus
+		if err != nil {
+			return nil
+		}
+	}
+}
+
+// StreamProgressWebSocket: GET /api/v1/campaigns/:id/ws, isi pesan sama
+// dengan StreamProgress dibungkus streamMessage
+func (h *streamHandler) StreamProgressWebSocket(c echo.Context) error {
+	updates, snapshot, unsubscribe, err := h.subscribe(c)
+	if err != nil {
+		return err
+	}
+	defer unsubscribe()
+
+	server := websocket.Server{
+		// data progress publik dan read-only, origin manapun boleh
+		Handshake: func(*websocket.Config, *http.Request) error {
+			return nil
+		},
+		Handler: func(conn *websocket.Conn) {
+			metrics.StreamConnections.WithLabelValues("websocket").Inc()
+			defer metrics.StreamConnections.WithLabelValues("websocket").Dec()
+
+			h.serveWebSocket(conn, updates, snapshot)
+		},
+	}
+
+	server.ServeHTTP(c.Response(), c.Request())
+	return nil
+}
+
+func (h *streamHandler) serveWebSocket(conn *websocket.Conn, updates <-chan campaign.Progress, snapshot campaign.Progress) {
+	defer conn.Close()
+
+	// pesan dari client tidak dipakai, dibaca hanya untuk tahu kapan koneksi putus
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		defer cancel()
+
+		var discard string
+		for websocket.Message.Receive(conn, &discard) == nil {
+		}
+	}()
+
+	send := func(message streamMessage) error {
+		conn.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+		return websocket.JSON.Send(conn, message)
+	}
+
+	err := send(streamMessage{Type: "progress", Data: &snapshot})
+	if err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case progress, ok := <-updates:
+			if !ok {
+				return
+			}
+
+			err = send(streamMessage{Type: "progress", Data: &progress})
+		case <-heartbeat.C:
+			err = send(streamMessage{Type: "ping"})
+		}
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+// subscribe mendaftar ke hub sebelum membaca campaign supaya tidak ada
+// update yang terlewat di antara snapshot dan pesan pertama dari redis
+func (h *streamHandler) subscribe(c echo.Context) (<-chan campaign.Progress, campaign.Progress, func(), error) {
+	var input campaign.GetCampaignDetailInput
+
+	// id yang bukan angka tidak mungkin ada
+	err := c.Bind(&input)
+	if err != nil {
+		return nil, campaign.Progress{}, nil, campaign.ErrCampaignNotFound
+	}
+
+	updates, unsubscribe := h.hub.Subscribe(input.ID)
+
+	snapshot, err := h.service.GetCampaignProgress(c.Request().Context(), input)
+	if err != nil {
+		unsubscribe()
+		return nil, campaign.Progress{}, nil, err
+	}
+
+	return updates, snapshot, unsubscribe, nil
+}
+
+func writeEvent(response *echo.Response, progress campaign.Progress) error {
+	data, err := json.Marshal(progress)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(response, "event: progress\ndata: %s\n\n", data)
+	if err != nil {
+		return err
+	}
+
+	response.Flush()
+	return nil
+}
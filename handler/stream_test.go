@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"auth-gorm-echo/campaign"
+	"auth-gorm-echo/logging"
+	"auth-gorm-echo/outbox"
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
+)
+
+// progressService hanya mengisi method yang dipakai streamHandler
+type progressService struct {
+	campaign.Service
+	progress campaign.Progress
+}
+
+func (s progressService) GetCampaignProgress(ctx context.Context, input campaign.GetCampaignDetailInput) (campaign.Progress, error) {
+	return s.progress, nil
+}
+
+// pledge yang settle menulis campaign.backed, relay meneruskannya ke
+// ProgressHub.PublishBacking dan client SSE harus menerima dana terbarunya
+func TestStreamProgressReceivesBacking(t *testing.T) {
+	server := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	hub := campaign.NewProgressHub(rdb, logging.New())
+	go hub.Run(ctx)
+
+	snapshot := campaign.Progress{CampaignID: 7, CurrentAmount: 1000, BackerCount: 1, NewBackers: []string{}}
+
+	router := echo.New()
+	router.GET("/api/v1/campaigns/:id/stream", NewStreamHandler(progressService{progress: snapshot}, hub).StreamProgress)
+
+	httpServer := httptest.NewServer(router)
+	t.Cleanup(httpServer.Close)
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, httpServer.URL+"/api/v1/campaigns/7/stream", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+
+	events := readProgressEvents(t, response)
+
+	first := <-events
+	if first.CurrentAmount != snapshot.CurrentAmount || first.BackerCount != snapshot.BackerCount {
+		t.Fatalf("snapshot = %+v, want %+v", first, snapshot)
+	}
+
+	// pesan yang dipublish sebelum PSUBSCRIBE aktif hilang
+	deadline := time.Now().Add(time.Second * 2)
+	for server.PubSubNumPat() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("progress hub did not subscribe to redis")
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+
+	payload, err := json.Marshal(campaign.BackingEvent{
+		CampaignEvent: campaign.CampaignEvent{CampaignID: 7, CurrentAmount: 1500, BackerCount: 2},
+		Amount:        500,
+		BackerName:    "Budi",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = hub.PublishBacking(ctx, outbox.Event{Type: campaign.EventCampaignBacked, AggregateID: 7, Payload: outbox.JSON(payload)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case progress := <-events:
+		if progress.CurrentAmount != 1500 || progress.BackerCount != 2 || len(progress.NewBackers) != 1 || progress.NewBackers[0] != "Budi" {
+			t.Fatalf("progress = %+v, want current_amount 1500, backer_count 2, new_backers [Budi]", progress)
+		}
+	case <-time.After(time.Second * 2):
+		t.Fatal("stream did not receive the backing")
+	}
+}
+
+// readProgressEvents membaca data setiap event "progress" dari response SSE
+func readProgressEvents(t *testing.T, response *http.Response) <-chan campaign.Progress {
+	events := make(chan campaign.Progress, 4)
+
+	go func() {
+		scanner := bufio.NewScanner(response.Body)
+		for scanner.Scan() {
+			data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+			if !ok {
+				continue
+			}
+
+			var progress campaign.Progress
+			err := json.Unmarshal([]byte(data), &progress)
+			if err != nil {
+				t.Errorf("invalid progress event %q: %v", data, err)
+				return
+			}
+
+			events <- progress
+		}
+	}()
+
+	return events
+}
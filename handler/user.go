@@ -4,13 +4,15 @@ import (
 	"auth-gorm-echo/auth"
 	"auth-gorm-echo/config"
 	"auth-gorm-echo/helper"
+	"auth-gorm-echo/oauth"
+	"auth-gorm-echo/upload"
 	"auth-gorm-echo/user"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
-	"io/ioutil"
+	"log/slog"
+	"math"
 	"net/http"
-	"os"
 	"strconv"
 
 	"github.com/labstack/echo/v4"
@@ -19,6 +21,9 @@ import (
 type userHandler struct {
 	userService user.Service
 	authService auth.Service
+	oauthProviders map[string]oauth.Provider
+	uploadService upload.Service
+	logger *slog.Logger
 }
 
 type RequestRedis struct {
@@ -26,8 +31,8 @@ type RequestRedis struct {
 	Token string
 }
 
-func NewUserHandler(userService user.Service, authService auth.Service) *userHandler {
-	return &userHandler{userService, authService}
+func NewUserHandler(userService user.Service, authService auth.Service, oauthProviders map[string]oauth.Provider, uploadService upload.Service, logger *slog.Logger) *userHandler {
+	return &userHandler{userService, authService, oauthProviders, uploadService, logger}
 }
 
 func (h *userHandler) RegisterUser(c echo.Context) error {
@@ -37,29 +42,28 @@ func (h *userHandler) RegisterUser(c echo.Context) error {
 
 	var input user.RegisterUserInput
 	err := c.Bind(&input)
-	if err := c.Validate(&input); err != nil {
-		errors := helper.FormatValidationError(err)
-		errorMessage := echo.Map{"errors": errors}
+	if err != nil {
+		return err
+	}
 
-		response := helper.APIResponse("Register account failed", http.StatusUnprocessableEntity, "error", errorMessage)
-		return c.JSON(http.StatusUnprocessableEntity, response)
+	err = c.Validate(&input)
+	if err != nil {
+		return err
 	}
 
-	newUser, err := h.userService.RegisterUser(input)
+	newUser, err := h.userService.RegisterUser(c.Request().Context(), input)
 	if err != nil {
-		response := helper.APIResponse("Register account failed", http.StatusBadRequest, "error", nil)
-		return c.JSON(http.StatusBadRequest, response)
+		return err
 	}
 
 	token, err := h.authService.GenerateToken(newUser.ID)
 	if err != nil {
-		response := helper.APIResponse("Register account failed", http.StatusBadRequest, "error", nil)
-		return c.JSON(http.StatusBadRequest, response)
+		return err
 	}
 
 	formatter := user.FormatUser(newUser, token)
 
-	response := helper.APIResponse("Account has been registered", http.StatusOK, "success", formatter)
+	response := helper.APIResponse("user.registered", http.StatusOK, "success", formatter)
 
 	return c.JSON(http.StatusOK, response)
 }
@@ -75,31 +79,125 @@ func (h *userHandler) Login(c echo.Context) error {
 	var input user.LoginInput
 
 	err := c.Bind(&input)
-	if err := c.Validate(&input); err != nil {
-		errors := helper.FormatValidationError(err)
-		errorMessage := echo.Map{"errors": errors}
+	if err != nil {
+		return err
+	}
+
+	err = c.Validate(&input)
+	if err != nil {
+		return err
+	}
+
+	input.IP = c.RealIP()
+
+	loggedInUser, err := h.userService.Login(c.Request().Context(), input)
+	if err != nil {
+		return loginFailedResponse(c, err)
+	}
+
+	return h.completeLogin(c, loggedInUser)
+}
+
+func (h *userHandler) OAuthLogin(c echo.Context) error {
+	// client melakukan redirect ke provider dengan PKCE, lalu mengirim
+	// authorization code dan code_verifier ke sini
+
+	provider, ok := h.oauthProviders[c.Param("provider")]
+	if !ok {
+		return oauth.ErrUnknownProvider
+	}
+
+	var input user.OAuthLoginInput
+
+	err := c.Bind(&input)
+	if err != nil {
+		return err
+	}
 
-		response := helper.APIResponse("Login failed", http.StatusUnprocessableEntity, "error", errorMessage)
-		return c.JSON(http.StatusUnprocessableEntity, response)
+	err = c.Validate(&input)
+	if err != nil {
+		return err
 	}
 
-	loggedInUser, err := h.userService.Login(input)
+	identity, err := provider.Exchange(c.Request().Context(), input.Code, input.CodeVerifier, input.RedirectURI)
 	if err != nil {
-		errorMessage := echo.Map{"errors": err.Error()}
+		// detail error dari provider hanya dicatat di log
+		h.logger.WarnContext(c.Request().Context(), "oauth code exchange failed", "provider", c.Param("provider"), "error", err)
+		return oauth.ErrExchangeFailed
+	}
 
-		response := helper.APIResponse("Login failed", http.StatusBadRequest, "error", errorMessage)
-		return c.JSON(http.StatusBadRequest, response)
+	loggedInUser, err := h.userService.LoginWithOAuth(c.Request().Context(), user.OAuthIdentityInput{
+		Provider: identity.Provider,
+		Subject: identity.Subject,
+		Email: identity.Email,
+		EmailVerified: identity.EmailVerified,
+		Name: identity.Name,
+	})
+	if err != nil {
+		return err
 	}
 
+	return h.completeLogin(c, loggedInUser)
+}
+
+// completeLogin meminta kode 2FA kalau user mengaktifkannya, kalau tidak
+// langsung membuat session
+func (h *userHandler) completeLogin(c echo.Context, loggedInUser user.User) error {
+	// user dengan 2FA harus memasukkan kode dulu di POST /sessions/2fa
+	if loggedInUser.TOTPEnabled {
+		mfaToken, err := h.authService.GenerateMFAToken(loggedInUser.ID)
+		if err != nil {
+			return err
+		}
+
+		formatter := user.FormatMFAChallenge(mfaToken)
+
+		response := helper.APIResponse("auth.mfa_required", http.StatusOK, "success", formatter)
+		return c.JSON(http.StatusOK, response)
+	}
+
+	return h.startSession(c, loggedInUser)
+}
+
+func (h *userHandler) VerifyTwoFactorLogin(c echo.Context) error {
+	var input user.TwoFactorLoginInput
+
+	err := c.Bind(&input)
+	if err != nil {
+		return err
+	}
+
+	err = c.Validate(&input)
+	if err != nil {
+		return err
+	}
+
+	claims, err := h.authService.ValidateMFAToken(input.MFAToken)
+	if err != nil {
+		response := helper.APIErrorResponse(auth.ErrorCode(err), http.StatusUnauthorized, auth.ErrorCode(err), nil)
+		return c.JSON(http.StatusUnauthorized, response)
+	}
+
+	input.UserID = claims.UserID
+	input.IP = c.RealIP()
+
+	loggedInUser, err := h.userService.VerifyTwoFactorLogin(c.Request().Context(), input)
+	if err != nil {
+		return loginFailedResponse(c, err)
+	}
+
+	return h.startSession(c, loggedInUser)
+}
+
+// startSession membuat JWT, menyimpan session di redis dan mengirim response login
+func (h *userHandler) startSession(c echo.Context, loggedInUser user.User) error {
 	token, err := h.authService.GenerateToken(loggedInUser.ID)
 	if err != nil {
-		response := helper.APIResponse("Login failed", http.StatusBadRequest, "error", nil)
-		return c.JSON(http.StatusBadRequest, response)
+		return err
 	}
 
 	// Redis Session
 	sessionExp := config.GetsessionExp()
-	redisCtx := config.GetRedisCtx()
 	rdb := config.RedisConnect()
 
 	userID := strconv.Itoa(loggedInUser.ID)
@@ -111,18 +209,44 @@ func (h *userHandler) Login(c echo.Context) error {
 	req, _ := json.Marshal(reqRedis)
 	
 	sessionID := fmt.Sprintf("session:%d", loggedInUser.ID)
-	err = rdb.Set(redisCtx, sessionID, req, sessionExp).Err()
+	err = rdb.Set(c.Request().Context(), sessionID, req, sessionExp).Err()
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Error saving session"})
+		return err
 	}
 
 	formatter := user.FormatUser(loggedInUser, token)
 
-	response := helper.APIResponse("Successfuly logged in", http.StatusOK, "success", formatter)
+	response := helper.APIResponse("auth.logged_in", http.StatusOK, "success", formatter)
 
 	return c.JSON(http.StatusOK, response)
 }
 
+func loginFailedResponse(c echo.Context, err error) error {
+	errorMessage := echo.Map{"errors": err.Error()}
+
+	var lockedErr *user.LoginLockedError
+	if errors.As(err, &lockedErr) {
+		return loginLockedResponse(c, lockedErr)
+	}
+
+	// saat login kode 2FA yang salah berarti login gagal, bukan input tidak valid
+	if errors.Is(err, user.ErrInvalidTwoFactorCode) {
+		response := helper.APIErrorResponse("auth.invalid_2fa_code", http.StatusUnauthorized, "auth.invalid_2fa_code", errorMessage)
+		return c.JSON(http.StatusUnauthorized, response)
+	}
+
+	return err
+}
+
+// loginLockedResponse dipakai semua endpoint yang memeriksa kode / password
+// lewat login guard, 429 dengan Retry-After sampai kunci dibuka
+func loginLockedResponse(c echo.Context, lockedErr *user.LoginLockedError) error {
+	c.Response().Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(lockedErr.RetryAfter.Seconds()))))
+
+	response := helper.APIErrorResponse("auth.login_locked", http.StatusTooManyRequests, "auth.login_locked", echo.Map{"errors": lockedErr.Error()})
+	return c.JSON(http.StatusTooManyRequests, response)
+}
+
 func (h *userHandler) CheckEmailAvailability(c echo.Context) error {
 	// input email dari user
 	// input email di mapping ke struct input
@@ -133,30 +257,28 @@ func (h *userHandler) CheckEmailAvailability(c echo.Context) error {
 	var input user.CheckEmailInput
 	
 	err := c.Bind(&input)
-	if err := c.Validate(&input); err != nil {
-		errors := helper.FormatValidationError(err)
-		errorMessage := echo.Map{"errors": errors}
-
-		response := helper.APIResponse("Email checking failed", http.StatusUnprocessableEntity, "error", errorMessage)
-		return c.JSON(http.StatusUnprocessableEntity, response)
+	if err != nil {
+		return err
 	}
 
-	isEmailAvailable, err := h.userService.IsEmailAvailable(input)
+	err = c.Validate(&input)
 	if err != nil {
-		errorMessage := echo.Map{"errors": "Server error"}
+		return err
+	}
 
-		response := helper.APIResponse("Email checking failed", http.StatusBadRequest, "error", errorMessage)
-		return c.JSON(http.StatusBadRequest, response)
+	isEmailAvailable, err := h.userService.IsEmailAvailable(c.Request().Context(), input)
+	if err != nil {
+		return err
 	}
 
 	data := echo.Map{
 		"is_available": isEmailAvailable,
 	}
 
-	metaMessage := "Email has been registered"
+	metaMessage := "user.email_registered"
 
 	if isEmailAvailable {
-		metaMessage = "Email is available"
+		metaMessage = "user.email_available"
 	}
 
 	response := helper.APIResponse(metaMessage, http.StatusOK, "success", data)
@@ -168,73 +290,126 @@ func (h *userHandler) FetchUser(c echo.Context) error {
 
 	formatter := user.FormatUser(currentUser, "")
 
-	response := helper.APIResponse("Successfuly fetch user data", http.StatusOK, "success", formatter)
+	response := helper.APIResponse("user.fetched", http.StatusOK, "success", formatter)
 
 	return c.JSON(http.StatusOK, response)
 }
 
 func (h *userHandler) UploadAvatar(c echo.Context) error {
 	// input dari user
-	// simpan gambar di folder "images/"
+	// validasi & simpan gambar (beserta thumbnail) ke storage lewat upload service
 	// di service panggil repository - user
 	// repo ambil data user yang login berdasarkan jwt
 	// repo update data user simpan lokasi file
 
 	file, err := c.FormFile("avatar")
 	if err != nil {
-		data := echo.Map{"is_uploaded": false}
-		response := helper.APIResponse("Failed to upload avatar image", http.StatusBadRequest, "error", data)
-		return c.JSON(http.StatusBadRequest, response)
+		return upload.ErrMissingFile
 	}
 
 	currentUser := c.Get("currentUser").(user.User)
 	userID := currentUser.ID
 
-	path := fmt.Sprintf("images/%d-%s", userID, file.Filename)
-
 	// source file
 	src, err := file.Open()
 	if err != nil {
-		data := echo.Map{"is_uploaded": false}
-		response := helper.APIResponse("Failed to upload avatar image", http.StatusBadRequest, "error", data)
-		return c.JSON(http.StatusBadRequest, response)
+		return err
 	}
 	defer src.Close()
 
-	// only jpg,jpeg,png allowed
-	fileByte, _ := ioutil.ReadFile(path)
-	fileType := http.DetectContentType(fileByte)
+	avatar, err := h.uploadService.SaveImage(c.Request().Context(), src)
+	if err != nil {
+		return err
+	}
+
+	_, err = h.userService.SaveAvatar(c.Request().Context(), userID, avatar.Path)
+	if err != nil {
+		deleteErr := h.uploadService.DeleteImage(c.Request().Context(), avatar.Path)
+		if deleteErr != nil {
+			h.logger.WarnContext(c.Request().Context(), "failed to delete orphaned avatar", "key", avatar.Path, "error", deleteErr)
+		}
+
+		return err
+	}
+
+	// avatar lama sudah tidak dipakai
+	if currentUser.AvatarFileName != "" && currentUser.AvatarFileName != avatar.Path {
+		err = h.uploadService.DeleteImage(c.Request().Context(), currentUser.AvatarFileName)
+		if err != nil {
+			h.logger.WarnContext(c.Request().Context(), "failed to delete old avatar", "key", currentUser.AvatarFileName, "error", err)
+		}
+	}
+
+	data := echo.Map{"is_uploaded": true}
+	response := helper.APIResponse("user.avatar_uploaded", http.StatusOK, "success", data)
+	return c.JSON(http.StatusOK, response)
+}
+
+func (h *userHandler) SetupTwoFactor(c echo.Context) error {
+	currentUser := c.Get("currentUser").(user.User)
+
+	setup, err := h.userService.SetupTwoFactor(c.Request().Context(), currentUser.ID)
+	if err != nil {
+		return err
+	}
+
+	formatter := user.FormatTwoFactorSetup(setup)
+
+	response := helper.APIResponse("2fa.setup_started", http.StatusOK, "success", formatter)
+	return c.JSON(http.StatusOK, response)
+}
 
-	if fileType != "image/jpeg" && fileType != "image/jpg" && fileType != "image/png" {
-		data := echo.Map{"is_uploaded": false}
-		response := helper.APIResponse("Only JPG/JPEG/PNG image is allowed", http.StatusBadRequest, "error", data)
-		return c.JSON(http.StatusBadRequest, response)
+func (h *userHandler) ConfirmTwoFactor(c echo.Context) error {
+	var input user.TwoFactorCodeInput
+
+	err := c.Bind(&input)
+	if err != nil {
+		return err
 	}
 
-	// destination file
-	dst, err := os.Create(path)
+	err = c.Validate(&input)
 	if err != nil {
-		data := echo.Map{"is_uploaded": false}
-		response := helper.APIResponse("Failed to upload avatar image", http.StatusBadRequest, "error", data)
-		return c.JSON(http.StatusBadRequest, response)
+		return err
 	}
-	defer dst.Close()
 
-	// copy
-	if _, err = io.Copy(dst, src); err != nil {
-		data := echo.Map{"is_uploaded": false}
-		response := helper.APIResponse("Failed to upload avatar image", http.StatusBadRequest, "error", data)
-		return c.JSON(http.StatusBadRequest, response)
+	currentUser := c.Get("currentUser").(user.User)
+
+	recoveryCodes, err := h.userService.ConfirmTwoFactor(c.Request().Context(), currentUser.ID, input)
+	if err != nil {
+		return err
 	}
 
-	_, err = h.userService.SaveAvatar(userID, path)
+	data := echo.Map{"recovery_codes": recoveryCodes}
+
+	response := helper.APIResponse("2fa.enabled", http.StatusOK, "success", data)
+	return c.JSON(http.StatusOK, response)
+}
+
+func (h *userHandler) DisableTwoFactor(c echo.Context) error {
+	var input user.TwoFactorCodeInput
+
+	err := c.Bind(&input)
 	if err != nil {
-		data := echo.Map{"is_uploaded": false}
-		response := helper.APIResponse("Failed to upload avatar image", http.StatusBadRequest, "error", data)
-		return c.JSON(http.StatusBadRequest, response)
+		return err
 	}
 
-	data := echo.Map{"is_uploaded": true}
-	response := helper.APIResponse("Avatar successfully uploaded", http.StatusOK, "success", data)
+	err = c.Validate(&input)
+	if err != nil {
+		return err
+	}
+
+	currentUser := c.Get("currentUser").(user.User)
+	input.IP = c.RealIP()
+
+	err = h.userService.DisableTwoFactor(c.Request().Context(), currentUser.ID, input)
+	var lockedErr *user.LoginLockedError
+	if errors.As(err, &lockedErr) {
+		return loginLockedResponse(c, lockedErr)
+	}
+	if err != nil {
+		return err
+	}
+
+	response := helper.APIResponse("2fa.disabled", http.StatusOK, "success", nil)
 	return c.JSON(http.StatusOK, response)
-}
\ No newline at end of file
+}
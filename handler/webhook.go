@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"auth-gorm-echo/helper"
+	"auth-gorm-echo/user"
+	"auth-gorm-echo/webhook"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type webhookHandler struct {
+	service webhook.Service
+}
+
+func NewWebhookHandler(service webhook.Service) *webhookHandler {
+	return &webhookHandler{service}
+}
+
+func (h *webhookHandler) CreateEndpoint(c echo.Context) error {
+	var input webhook.CreateEndpointInput
+
+	err := c.Bind(&input)
+	if err != nil {
+		return err
+	}
+
+	err = c.Validate(&input)
+	if err != nil {
+		return err
+	}
+
+	currentUser := c.Get("currentUser").(user.User)
+	input.User = currentUser
+
+	newEndpoint, err := h.service.CreateEndpoint(c.Request().Context(), input)
+	if err != nil {
+		return err
+	}
+
+	response := helper.APIResponse("webhook.created", http.StatusOK, "success", webhook.FormatCreatedEndpoint(newEndpoint))
+	return c.JSON(http.StatusOK, response)
+}
+
+func (h *webhookHandler) GetEndpoints(c echo.Context) error {
+	currentUser := c.Get("currentUser").(user.User)
+
+	endpoints, err := h.service.GetEndpoints(c.Request().Context(), currentUser.ID)
+	if err != nil {
+		return err
+	}
+
+	response := helper.APIResponse("webhook.list", http.StatusOK, "success", webhook.FormatEndpoints(endpoints))
+	return c.JSON(http.StatusOK, response)
+}
+
+func (h *webhookHandler) DeleteEndpoint(c echo.Context) error {
+	input, err := bindEndpointInput(c)
+	if err != nil {
+		return err
+	}
+
+	err = h.service.DeleteEndpoint(c.Request().Context(), input)
+	if err != nil {
+		return err
+	}
+
+	response := helper.APIResponse("webhook.deleted", http.StatusOK, "success", nil)
+	return c.JSON(http.StatusOK, response)
+}
+
+func (h *webhookHandler) SendTestEvent(c echo.Context) error {
+	input, err := bindEndpointInput(c)
+	if err != nil {
+		return err
+	}
+
+	err = h.service.SendTestEvent(c.Request().Context(), input)
+	if err != nil {
+		return err
+	}
+
+	response := helper.APIResponse("webhook.test_sent", http.StatusOK, "success", nil)
+	return c.JSON(http.StatusOK, response)
+}
+
+func (h *webhookHandler) GetDeliveries(c echo.Context) error {
+	input, err := bindEndpointInput(c)
+	if err != nil {
+		return err
+	}
+
+	deliveries, err := h.service.GetDeliveries(c.Request().Context(), input)
+	if err != nil {
+		return err
+	}
+
+	response := helper.APIResponse("webhook.deliveries", http.StatusOK, "success", webhook.FormatDeliveries(deliveries))
+	return c.JSON(http.StatusOK, response)
+}
+
+// id yang bukan angka tidak mungkin ada
+func bindEndpointInput(c echo.Context) (webhook.EndpointInput, error) {
+	var input webhook.EndpointInput
+
+	err := c.Bind(&input)
+	if err != nil {
+		return input, webhook.ErrEndpointNotFound
+	}
+
+	input.User = c.Get("currentUser").(user.User)
+
+	return input, nil
+}
@@ -1,6 +1,6 @@
 package helper
 
-import "github.com/go-playground/validator/v10"
+import "auth-gorm-echo/i18n"
 
 type Response struct {
 	Meta Meta		`json:"meta"`
@@ -9,17 +9,28 @@ type Response struct {
 
 type Meta struct {
 	Message string		`json:"message"`
+	MessageKey string	`json:"message_key,omitempty"`
 	Code int		`json:"code"`
 	Status string		`json:"status"`
+	ErrorCode string	`json:"error_code,omitempty"`
+	RequestID string	`json:"request_id,omitempty"`
 }
 
-func APIResponse(message string, code int, status string, data interface{}) Response {
+// APIResponse menerima key dari katalog i18n (contoh "campaign.list"), pesan
+// diterjemahkan sesuai Accept-Language saat response ditulis oleh JSONSerializer.
+// Teks biasa yang tidak ada di katalog dikirim apa adanya.
+func APIResponse(messageKey string, code int, status string, data interface{}) Response {
 	meta := Meta{
-		Message: message,
+		Message: messageKey,
 		Code: code,
 		Status: status,
 	}
 
+	if message, ok := i18n.Translate(i18n.DefaultLocale, messageKey); ok {
+		meta.Message = message
+		meta.MessageKey = messageKey
+	}
+
 	jsonResponse := Response{
 		Meta: meta,
 		Data: data,
@@ -28,13 +39,12 @@ func APIResponse(message string, code int, status string, data interface{}) Resp
 	return jsonResponse
 }
 
-func FormatValidationError(err error) []string {
-	var errors []string
+// APIErrorResponse sama seperti APIResponse tapi menyertakan kode error
+// yang stabil supaya client tidak perlu mem-parsing message
+func APIErrorResponse(messageKey string, code int, errorCode string, data interface{}) Response {
+	jsonResponse := APIResponse(messageKey, code, "error", data)
+	jsonResponse.Meta.ErrorCode = errorCode
 
-	for _, e := range err.(validator.ValidationErrors) {
-		errors = append(errors, e.Error())
-	}
-
-	return errors
+	return jsonResponse
 }
 
@@ -0,0 +1,30 @@
+package helper
+
+import (
+	"auth-gorm-echo/i18n"
+
+	"github.com/labstack/echo/v4"
+)
+
+// JSONSerializer menerjemahkan meta.message ke bahasa request dan menambahkan
+// request ID ke meta response error supaya keluhan dari client bisa
+// dicocokkan dengan log
+type JSONSerializer struct {
+	echo.DefaultJSONSerializer
+}
+
+func (s JSONSerializer) Serialize(c echo.Context, i interface{}, indent string) error {
+	if response, ok := i.(Response); ok {
+		if response.Meta.MessageKey != "" {
+			response.Meta.Message = i18n.T(i18n.Locale(c.Request().Context()), response.Meta.MessageKey)
+		}
+
+		if response.Meta.Status == "error" {
+			response.Meta.RequestID = c.Response().Header().Get(echo.HeaderXRequestID)
+		}
+
+		i = response
+	}
+
+	return s.DefaultJSONSerializer.Serialize(c, i, indent)
+}
@@ -0,0 +1,214 @@
+package i18n
+
+// catalog berisi pesan response per bahasa. Key dikirim ke client di
+// meta.message_key dan tidak boleh diubah, yang boleh diubah hanya teksnya.
+var catalog = map[string]map[string]string{
+	"id": {
+		// user & session
+		"user.registered":        "Akun berhasil didaftarkan",
+		"user.fetched":           "Data user berhasil diambil",
+		"user.email_available":   "Email tersedia",
+		"user.email_registered":  "Email sudah terdaftar",
+		"user.avatar_uploaded":   "Avatar berhasil diunggah",
+		"user.not_found":         "User tidak ditemukan",
+		"user.email_taken":       "Email sudah terdaftar",
+		"auth.logged_in":         "Berhasil masuk",
+		"auth.mfa_required":      "Autentikasi dua faktor diperlukan",
+		"2fa.setup_started":      "Pindai kode QR lalu konfirmasi dengan kode dari aplikasi authenticator",
+		"2fa.enabled":            "Autentikasi dua faktor berhasil diaktifkan",
+		"2fa.disabled":           "Autentikasi dua faktor berhasil dinonaktifkan",
+		"2fa.already_enabled":    "Autentikasi dua faktor sudah aktif",
+		"2fa.not_setup":          "Autentikasi dua faktor belum disiapkan",
+		"2fa.invalid_code":       "Kode autentikasi dua faktor tidak valid",
+		"oauth.unknown_provider": "Provider OAuth tidak dikenal",
+		"oauth.exchange_failed":  "Gagal memverifikasi authorization code",
+		"oauth.unverified_email": "Email dari provider belum terverifikasi",
+
+		// autentikasi
+		"auth.unauthorized":        "Tidak terautentikasi",
+		"auth.forbidden":           "Akses ditolak",
+		"auth.missing_token":       "Token tidak ditemukan",
+		"auth.malformed_token":     "Format token tidak valid",
+		"auth.invalid_token":       "Token tidak valid",
+		"auth.token_expired":       "Token sudah kedaluwarsa",
+		"auth.user_not_found":      "User pemilik token tidak ditemukan",
+		"auth.invalid_api_key":     "API key tidak valid",
+		"auth.api_key_expired":     "API key sudah kedaluwarsa",
+		"auth.api_key_not_allowed": "API key tidak bisa dipakai untuk endpoint ini",
+		"auth.insufficient_scope":  "API key tidak memiliki scope yang dibutuhkan",
+		"auth.invalid_credentials": "Email atau password salah",
+		"auth.invalid_2fa_code":    "Kode autentikasi dua faktor salah",
+		"auth.login_locked":        "Terlalu banyak percobaan login yang gagal, coba lagi nanti",
+		"auth.admin_required":      "Endpoint ini hanya untuk admin",
+
+		// campaign
+		"campaign.list":             "Daftar campaign",
+		"campaign.detail":           "Detail campaign",
+		"campaign.created":          "Campaign berhasil dibuat",
+		"campaign.image_uploaded":   "Gambar campaign berhasil diunggah",
+		"campaign.not_found":        "Campaign tidak ditemukan",
+		"campaign.not_owner":        "Anda bukan pemilik campaign ini",
+		"campaign.deadline_in_past": "Deadline harus di masa depan",
+
+		// pledge
+		"pledge.created":         "Pledge berhasil dibuat, selesaikan pembayaran untuk mendukung campaign",
+		"pledge.list":            "Daftar pledge campaign",
+		"pledge.paid":            "Pembayaran pledge berhasil dicatat",
+		"pledge.refunded":        "Pledge berhasil di-refund",
+		"pledge.not_found":       "Pledge tidak ditemukan",
+		"pledge.campaign_closed": "Campaign sudah tidak menerima pledge",
+		"pledge.invalid_status":  "Status pledge tidak bisa diubah",
+
+		// api key
+		"api_key.created":   "API key berhasil dibuat, salin sekarang karena tidak akan ditampilkan lagi",
+		"api_key.list":      "Daftar API key",
+		"api_key.deleted":   "API key berhasil dihapus",
+		"api_key.not_found": "API key tidak ditemukan",
+
+		// webhook
+		"webhook.created":    "Webhook berhasil dibuat, salin secret sekarang karena tidak akan ditampilkan lagi",
+		"webhook.list":       "Daftar webhook",
+		"webhook.deleted":    "Webhook berhasil dihapus",
+		"webhook.not_found":  "Webhook tidak ditemukan",
+		"webhook.test_sent":  "Event test dimasukkan ke antrian pengiriman",
+		"webhook.deliveries": "Log pengiriman webhook",
+
+		// job
+		"job.stats":     "Statistik antrian job",
+		"job.dead_list": "Daftar job yang gagal",
+		"job.retried":   "Job dimasukkan kembali ke antrian",
+		"job.not_found": "Job tidak ditemukan",
+		"job.duplicate": "Job dengan ID yang sama sudah ada di antrian",
+
+		// upload
+		"upload.missing_file":         "File wajib diunggah",
+		"upload.file_too_large":       "Ukuran file terlalu besar",
+		"upload.unsupported_type":     "Hanya gambar JPG/JPEG/PNG yang diperbolehkan",
+		"upload.invalid_image":        "File bukan gambar yang valid",
+		"upload.dimensions_too_large": "Dimensi gambar terlalu besar",
+		"upload.dimensions_too_small": "Dimensi gambar terlalu kecil",
+
+		// request & server
+		"validation.failed":              "Validasi gagal",
+		"request.error":                  "Request tidak dapat diproses",
+		"request.bad_request":            "Request tidak valid",
+		"request.malformed_body":         "Body request bukan JSON yang valid",
+		"request.invalid_parameter":      "Request berisi parameter yang tidak valid",
+		"request.too_large":              "Ukuran request terlalu besar",
+		"rate_limit.exceeded":            "Terlalu banyak request, coba lagi nanti",
+		"request.unsupported_media_type": "Content type tidak didukung",
+		"request.too_many_requests":      "Terlalu banyak request",
+		"route.not_found":                "Halaman tidak ditemukan",
+		"route.method_not_allowed":       "Method tidak diizinkan",
+		"server.internal_error":          "Terjadi kesalahan pada server",
+		"server.unavailable":             "Layanan sedang tidak tersedia",
+		"health.ok":                      "OK",
+		"health.ready":                   "Siap",
+		"health.not_ready":               "Belum siap",
+		"health.draining":                "Server sedang dimatikan",
+	},
+	"en": {
+		// user & session
+		"user.registered":        "Account has been registered",
+		"user.fetched":           "Successfuly fetch user data",
+		"user.email_available":   "Email is available",
+		"user.email_registered":  "Email has been registered",
+		"user.avatar_uploaded":   "Avatar successfully uploaded",
+		"user.not_found":         "No user found on that ID",
+		"user.email_taken":       "Email has been registered",
+		"auth.logged_in":         "Successfuly logged in",
+		"auth.mfa_required":      "Two-factor authentication required",
+		"2fa.setup_started":      "Scan the QR code and confirm with a code from your authenticator app",
+		"2fa.enabled":            "Two-factor authentication has been enabled",
+		"2fa.disabled":           "Two-factor authentication has been disabled",
+		"2fa.already_enabled":    "Two-factor authentication is already enabled",
+		"2fa.not_setup":          "Two-factor authentication has not been set up",
+		"2fa.invalid_code":       "Invalid two-factor authentication code",
+		"oauth.unknown_provider": "Unknown OAuth provider",
+		"oauth.exchange_failed":  "Failed to verify authorization code",
+		"oauth.unverified_email": "Email from the provider is not verified",
+
+		// autentikasi
+		"auth.unauthorized":        "Unauthorized",
+		"auth.forbidden":           "Forbidden",
+		"auth.missing_token":       "Token is missing",
+		"auth.malformed_token":     "Token is malformed",
+		"auth.invalid_token":       "Token is invalid",
+		"auth.token_expired":       "Token has expired",
+		"auth.user_not_found":      "Token owner not found",
+		"auth.invalid_api_key":     "Invalid API key",
+		"auth.api_key_expired":     "API key has expired",
+		"auth.api_key_not_allowed": "API keys cannot be used for this endpoint",
+		"auth.insufficient_scope":  "API key does not have the required scope",
+		"auth.invalid_credentials": "Invalid email or password",
+		"auth.invalid_2fa_code":    "Invalid two-factor authentication code",
+		"auth.login_locked":        "Too many failed login attempts, try again later",
+		"auth.admin_required":      "This endpoint is only available to admins",
+
+		// campaign
+		"campaign.list":             "List of campaigns",
+		"campaign.detail":           "Campaign detail",
+		"campaign.created":          "Campaign has been created",
+		"campaign.image_uploaded":   "Campaign image successfully uploaded",
+		"campaign.not_found":        "Campaign not found",
+		"campaign.not_owner":        "Not an owner of the campaign",
+		"campaign.deadline_in_past": "Deadline must be in the future",
+
+		// pledge
+		"pledge.created":         "Pledge has been created, complete the payment to back the campaign",
+		"pledge.list":            "List of campaign pledges",
+		"pledge.paid":            "Pledge payment has been recorded",
+		"pledge.refunded":        "Pledge has been refunded",
+		"pledge.not_found":       "Pledge not found",
+		"pledge.campaign_closed": "Campaign is no longer accepting pledges",
+		"pledge.invalid_status":  "Pledge status cannot be changed",
+
+		// api key
+		"api_key.created":   "API key has been created, copy it now because it will not be shown again",
+		"api_key.list":      "List of API keys",
+		"api_key.deleted":   "API key has been deleted",
+		"api_key.not_found": "API key not found",
+
+		// webhook
+		"webhook.created":    "Webhook has been created, copy the secret now because it will not be shown again",
+		"webhook.list":       "List of webhooks",
+		"webhook.deleted":    "Webhook has been deleted",
+		"webhook.not_found":  "Webhook not found",
+		"webhook.test_sent":  "Test event has been queued for delivery",
+		"webhook.deliveries": "Webhook delivery log",
+
+		// job
+		"job.stats":     "Job queue statistics",
+		"job.dead_list": "Failed jobs",
+		"job.retried":   "Job has been queued again",
+		"job.not_found": "Job not found",
+		"job.duplicate": "A job with the same ID is already queued",
+
+		// upload
+		"upload.missing_file":         "File is required",
+		"upload.file_too_large":       "File is too large",
+		"upload.unsupported_type":     "Only JPG/JPEG/PNG image is allowed",
+		"upload.invalid_image":        "File is not a valid image",
+		"upload.dimensions_too_large": "Image dimensions are too large",
+		"upload.dimensions_too_small": "Image dimensions are too small",
+
+		// request & server
+		"validation.failed":              "Validation failed",
+		"request.error":                  "Request could not be processed",
+		"request.bad_request":            "Bad request",
+		"request.malformed_body":         "Request body is not valid JSON",
+		"request.invalid_parameter":      "Request contains an invalid parameter",
+		"request.too_large":              "Request is too large",
+		"rate_limit.exceeded":            "Too many requests, try again later",
+		"request.unsupported_media_type": "Unsupported content type",
+		"request.too_many_requests":      "Too many requests",
+		"route.not_found":                "Not found",
+		"route.method_not_allowed":       "Method not allowed",
+		"server.internal_error":          "Internal server error",
+		"server.unavailable":             "Service is temporarily unavailable",
+		"health.ok":                      "OK",
+		"health.ready":                   "Ready",
+		"health.not_ready":               "Not ready",
+		"health.draining":                "Shutting down",
+	},
+}
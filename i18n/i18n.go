@@ -0,0 +1,111 @@
+package i18n
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// DefaultLocale dipakai kalau Accept-Language kosong atau tidak ada bahasa
+// yang didukung, pengguna utama aplikasi ini berbahasa Indonesia
+const DefaultLocale = "id"
+
+type contextKey struct{}
+
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, contextKey{}, locale)
+}
+
+// Locale mengembalikan bahasa hasil negosiasi Middleware
+func Locale(ctx context.Context) string {
+	locale, ok := ctx.Value(contextKey{}).(string)
+	if !ok {
+		return DefaultLocale
+	}
+
+	return locale
+}
+
+// Translate mengembalikan pesan untuk key, ok bernilai false kalau key tidak
+// ada di katalog
+func Translate(locale string, key string) (string, bool) {
+	text, ok := catalog[locale][key]
+	if !ok {
+		text, ok = catalog[DefaultLocale][key]
+	}
+
+	return text, ok
+}
+
+// T sama seperti Translate tapi mengembalikan key kalau pesan tidak ditemukan
+func T(locale string, key string) string {
+	text, ok := Translate(locale, key)
+	if !ok {
+		return key
+	}
+
+	return text
+}
+
+// Negotiate memilih bahasa yang didukung dari header Accept-Language
+// sesuai urutan q-value, contoh "en-US,en;q=0.9,id;q=0.8" menjadi "en"
+func Negotiate(acceptLanguage string) string {
+	type candidate struct {
+		locale  string
+		quality float64
+	}
+
+	candidates := []candidate{}
+
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+
+		tag := strings.ToLower(strings.TrimSpace(fields[0]))
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		quality := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				q, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64)
+				if err == nil {
+					quality = q
+				}
+			}
+		}
+
+		candidates = append(candidates, candidate{strings.SplitN(tag, "-", 2)[0], quality})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].quality > candidates[j].quality
+	})
+
+	for _, c := range candidates {
+		if _, ok := catalog[c.locale]; ok && c.quality > 0 {
+			return c.locale
+		}
+	}
+
+	return DefaultLocale
+}
+
+// Middleware menyimpan bahasa hasil negosiasi di context request
+func Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			locale := Negotiate(c.Request().Header.Get("Accept-Language"))
+
+			c.SetRequest(c.Request().WithContext(WithLocale(c.Request().Context(), locale)))
+			c.Response().Header().Set("Content-Language", locale)
+			c.Response().Header().Add(echo.HeaderVary, "Accept-Language")
+
+			return next(c)
+		}
+	}
+}
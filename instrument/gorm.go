@@ -0,0 +1,43 @@
+package instrument
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// GormCallbacks membuat callback yang dipasang sebelum dan sesudah setiap
+// operasi GORM (create, query, update, delete, row, raw)
+type GormCallbacks struct {
+	Before func(operation string) func(*gorm.DB)
+	After  func(operation string) func(*gorm.DB)
+}
+
+// RegisterGormCallbacks dipakai plugin GORM di package metrics dan tracing,
+// nama callback diberi prefix nama plugin (contoh "tracing:before_query")
+func RegisterGormCallbacks(db *gorm.DB, prefix string, callbacks GormCallbacks) error {
+	callback := db.Callback()
+	create, query, update := callback.Create(), callback.Query(), callback.Update()
+	del, row, raw := callback.Delete(), callback.Row(), callback.Raw()
+
+	return errors.Join(
+		register(prefix, "create", create.Before("gorm:create"), create.After("gorm:create"), callbacks),
+		register(prefix, "query", query.Before("gorm:query"), query.After("gorm:query"), callbacks),
+		register(prefix, "update", update.Before("gorm:update"), update.After("gorm:update"), callbacks),
+		register(prefix, "delete", del.Before("gorm:delete"), del.After("gorm:delete"), callbacks),
+		register(prefix, "row", row.Before("gorm:row"), row.After("gorm:row"), callbacks),
+		register(prefix, "raw", raw.Before("gorm:raw"), raw.After("gorm:raw"), callbacks),
+	)
+}
+
+// C adalah tipe callback GORM yang tidak diekspor, cukup butuh method Register
+func register[C interface {
+	Register(name string, fn func(*gorm.DB)) error
+}](prefix string, operation string, before C, after C, callbacks GormCallbacks) error {
+	err := before.Register(prefix+":before_"+operation, callbacks.Before(operation))
+	if err != nil {
+		return err
+	}
+
+	return after.Register(prefix+":after_"+operation, callbacks.After(operation))
+}
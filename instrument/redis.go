@@ -0,0 +1,43 @@
+package instrument
+
+import (
+	"context"
+	"net"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisHook membungkus setiap command dan pipeline redis. Start dipanggil
+// sebelum command dijalankan (command "pipeline" untuk pipeline), fungsi yang
+// dikembalikan dipanggil dengan error hasil command.
+type RedisHook struct {
+	Start func(ctx context.Context, command string) (context.Context, func(err error))
+}
+
+func (h RedisHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network string, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+func (h RedisHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		ctx, end := h.Start(ctx, cmd.Name())
+
+		err := next(ctx, cmd)
+		end(err)
+
+		return err
+	}
+}
+
+func (h RedisHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		ctx, end := h.Start(ctx, "pipeline")
+
+		err := next(ctx, cmds)
+		end(err)
+
+		return err
+	}
+}
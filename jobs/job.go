@@ -0,0 +1,73 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// Job disimpan di redis sebagai JSON, Payload adalah argumen handler
+type Job struct {
+	ID          string          `json:"id"`
+	Type        string          `json:"type"`
+	Payload     json.RawMessage `json:"payload"`
+	Attempts    int             `json:"attempts"`
+	MaxAttempts int             `json:"max_attempts"`
+	RunAt       time.Time       `json:"run_at"`
+	CreatedAt   time.Time       `json:"created_at"`
+	LastError   string          `json:"last_error,omitempty"`
+	FailedAt    *time.Time      `json:"failed_at,omitempty"`
+}
+
+type Option func(*Job)
+
+// Delay menjalankan job paling cepat setelah d
+func Delay(d time.Duration) Option {
+	return func(job *Job) {
+		job.RunAt = time.Now().Add(d)
+	}
+}
+
+// At menjalankan job paling cepat pada waktu t
+func At(t time.Time) Option {
+	return func(job *Job) {
+		job.RunAt = t
+	}
+}
+
+func MaxAttempts(n int) Option {
+	return func(job *Job) {
+		job.MaxAttempts = n
+	}
+}
+
+// ID mengganti id acak dengan id tetap, job dengan id yang masih ada di
+// antrian tidak akan dimasukkan dua kali (ErrDuplicateJob)
+func ID(id string) Option {
+	return func(job *Job) {
+		job.ID = id
+	}
+}
+
+// Type menghubungkan nama job dengan tipe payload-nya supaya Enqueue dan
+// Handle memakai struct yang sama
+type Type[T any] struct {
+	Name string
+}
+
+func NewType[T any](name string) Type[T] {
+	return Type[T]{Name: name}
+}
+
+func (t Type[T]) Enqueue(ctx context.Context, queue *Queue, payload T, options ...Option) (Job, error) {
+	return queue.Enqueue(ctx, t.Name, payload, options...)
+}
+
+type attemptKey struct{}
+
+// Attempt mengembalikan percobaan ke berapa job yang sedang dijalankan
+// handler, dimulai dari 1
+func Attempt(ctx context.Context) int {
+	attempt, _ := ctx.Value(attemptKey{}).(int)
+	return attempt
+}
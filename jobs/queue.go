@@ -0,0 +1,419 @@
+package jobs
+
+import (
+	"auth-gorm-echo/apperror"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	ErrJobNotFound  = apperror.NotFound("job.not_found", "Job not found")
+	ErrDuplicateJob = apperror.Conflict("job.duplicate", "Job with the same ID is already queued")
+)
+
+// key redis:
+//
+//	jobs:job:<id>     data job (JSON)
+//	jobs:ready        id job yang siap dijalankan (list)
+//	jobs:scheduled    id job yang ditunda, score = waktu jalan (sorted set)
+//	jobs:processing   id job yang sedang dikerjakan worker (list)
+//	jobs:lease:<id>   ada selama worker masih memegang job, hilang kalau worker mati
+//	jobs:dead         id job yang gagal permanen, score = waktu gagal (sorted set)
+const (
+	keyReady      = "jobs:ready"
+	keyScheduled  = "jobs:scheduled"
+	keyProcessing = "jobs:processing"
+	keyDead       = "jobs:dead"
+)
+
+func keyJob(id string) string {
+	return "jobs:job:" + id
+}
+
+func keyLease(id string) string {
+	return "jobs:lease:" + id
+}
+
+// KEYS[1] job, KEYS[2] ready, KEYS[3] scheduled
+// ARGV[1] JSON job, ARGV[2] id, ARGV[3] waktu jalan (ms) atau 0 untuk langsung
+var enqueueScript = redis.NewScript(`
+if not redis.call('SET', KEYS[1], ARGV[1], 'NX') then
+	return 0
+end
+
+if tonumber(ARGV[3]) > 0 then
+	redis.call('ZADD', KEYS[3], ARGV[3], ARGV[2])
+else
+	redis.call('LPUSH', KEYS[2], ARGV[2])
+end
+
+return 1
+`)
+
+// memindahkan job ke processing sekaligus memasang lease, kalau dua langkah
+// terpisah requeueExpired bisa melihat job tanpa lease lalu menjalankannya dua kali.
+// Key lease baru diketahui setelah LMOVE jadi prefix-nya dikirim lewat ARGV.
+// KEYS[1] ready, KEYS[2] processing, ARGV[1] prefix lease, ARGV[2] visibility timeout (ms)
+var fetchScript = redis.NewScript(`
+local id = redis.call('LMOVE', KEYS[1], KEYS[2], 'RIGHT', 'LEFT')
+if not id then
+	return false
+end
+
+redis.call('SET', ARGV[1] .. id, '1', 'PX', ARGV[2])
+return id
+`)
+
+// KEYS[1] scheduled, KEYS[2] ready, ARGV[1] sekarang (ms)
+var promoteScript = redis.NewScript(`
+local ids = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, 100)
+for _, id in ipairs(ids) do
+	redis.call('ZREM', KEYS[1], id)
+	redis.call('LPUSH', KEYS[2], id)
+end
+
+return #ids
+`)
+
+// KEYS[1] lease, KEYS[2] processing, KEYS[3] ready, ARGV[1] id
+var requeueScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 1 then
+	return 0
+end
+
+if redis.call('LREM', KEYS[2], 1, ARGV[1]) == 0 then
+	return 0
+end
+
+redis.call('RPUSH', KEYS[3], ARGV[1])
+return 1
+`)
+
+type QueueConfig struct {
+	// lama worker boleh memegang job sebelum dianggap mati dan job diulang
+	VisibilityTimeout time.Duration
+	// nilai MaxAttempts kalau tidak diisi saat Enqueue
+	MaxAttempts int
+	// lama job di dead letter queue disimpan sebelum dihapus
+	DeadRetention time.Duration
+}
+
+// jeda antar percobaan fetch saat antrian kosong
+const fetchPollInterval = time.Millisecond * 200
+
+// Queue adalah antrian job di redis dengan at-least-once delivery: job yang
+// sedang dikerjakan saat worker mati akan dijalankan ulang, jadi handler
+// harus aman dijalankan lebih dari sekali
+type Queue struct {
+	rdb    *redis.Client
+	config QueueConfig
+}
+
+type Stats struct {
+	Ready      int64 `json:"ready"`
+	Scheduled  int64 `json:"scheduled"`
+	Processing int64 `json:"processing"`
+	Dead       int64 `json:"dead"`
+}
+
+func NewQueue(rdb *redis.Client, config QueueConfig) *Queue {
+	return &Queue{rdb, config}
+}
+
+func (q *Queue) Enqueue(ctx context.Context, jobType string, payload interface{}, options ...Option) (Job, error) {
+	now := time.Now()
+
+	job := Job{
+		Type:        jobType,
+		MaxAttempts: q.config.MaxAttempts,
+		RunAt:       now,
+		CreatedAt:   now,
+	}
+
+	for _, option := range options {
+		option(&job)
+	}
+
+	if job.ID == "" {
+		id, err := randomID()
+		if err != nil {
+			return job, err
+		}
+
+		job.ID = id
+	}
+
+	encodedPayload, err := json.Marshal(payload)
+	if err != nil {
+		return job, err
+	}
+	job.Payload = encodedPayload
+
+	encoded, err := json.Marshal(job)
+	if err != nil {
+		return job, err
+	}
+
+	var runAt int64
+	if job.RunAt.After(now) {
+		runAt = job.RunAt.UnixMilli()
+	}
+
+	created, err := enqueueScript.Run(ctx, q.rdb, []string{keyJob(job.ID), keyReady, keyScheduled}, encoded, job.ID, runAt).Int()
+	if err != nil {
+		return job, err
+	}
+
+	if created == 0 {
+		return job, ErrDuplicateJob
+	}
+
+	return job, nil
+}
+
+// fetch menunggu job paling lama timeout, ok bernilai false kalau antrian kosong
+func (q *Queue) fetch(ctx context.Context, timeout time.Duration) (job Job, ok bool, err error) {
+	// script redis tidak bisa blocking seperti BLMOVE, jadi antrian dicek berkala
+	deadline := time.Now().Add(timeout)
+
+	var id string
+	for {
+		id, err = fetchScript.Run(ctx, q.rdb, []string{keyReady, keyProcessing}, keyLease(""), q.config.VisibilityTimeout.Milliseconds()).Text()
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, redis.Nil) {
+			return job, false, err
+		}
+
+		if time.Now().Add(fetchPollInterval).After(deadline) {
+			return job, false, nil
+		}
+
+		time.Sleep(fetchPollInterval)
+	}
+
+	job, err = q.get(ctx, id)
+	if errors.Is(err, ErrJobNotFound) {
+		// data job sudah dihapus, buang id-nya saja
+		return job, false, q.rdb.LRem(ctx, keyProcessing, 1, id).Err()
+	}
+	if err != nil {
+		return job, false, err
+	}
+
+	job.Attempts++
+
+	err = q.save(ctx, q.rdb, job)
+	if err != nil {
+		return job, false, err
+	}
+
+	return job, true, nil
+}
+
+// complete menghapus job yang berhasil dijalankan
+func (q *Queue) complete(ctx context.Context, job Job) error {
+	_, err := q.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.LRem(ctx, keyProcessing, 1, job.ID)
+		pipe.Del(ctx, keyJob(job.ID), keyLease(job.ID))
+		return nil
+	})
+
+	return err
+}
+
+// retry menjadwalkan ulang job yang gagal pada runAt
+func (q *Queue) retry(ctx context.Context, job Job, runAt time.Time, jobErr error) error {
+	job.LastError = jobErr.Error()
+	job.RunAt = runAt
+
+	_, err := q.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		err := q.save(ctx, pipe, job)
+		if err != nil {
+			return err
+		}
+
+		pipe.LRem(ctx, keyProcessing, 1, job.ID)
+		pipe.Del(ctx, keyLease(job.ID))
+		pipe.ZAdd(ctx, keyScheduled, redis.Z{Score: float64(runAt.UnixMilli()), Member: job.ID})
+		return nil
+	})
+
+	return err
+}
+
+// bury memindahkan job ke dead letter queue, hanya bisa dijalankan lagi lewat
+// RetryDead sebelum DeadRetention habis
+func (q *Queue) bury(ctx context.Context, job Job, jobErr error) error {
+	now := time.Now()
+	job.LastError = jobErr.Error()
+	job.FailedAt = &now
+
+	_, err := q.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		encoded, err := json.Marshal(job)
+		if err != nil {
+			return err
+		}
+
+		pipe.Set(ctx, keyJob(job.ID), encoded, q.config.DeadRetention)
+		pipe.LRem(ctx, keyProcessing, 1, job.ID)
+		pipe.Del(ctx, keyLease(job.ID))
+		pipe.ZAdd(ctx, keyDead, redis.Z{Score: float64(now.UnixMilli()), Member: job.ID})
+
+		// id yang data job-nya sudah expired ikut dibuang
+		if q.config.DeadRetention > 0 {
+			pipe.ZRemRangeByScore(ctx, keyDead, "-inf", strconv.FormatInt(now.Add(-q.config.DeadRetention).UnixMilli(), 10))
+		}
+
+		return nil
+	})
+
+	return err
+}
+
+// promoteDue memindahkan job terjadwal yang sudah waktunya ke antrian ready
+func (q *Queue) promoteDue(ctx context.Context) (int, error) {
+	return promoteScript.Run(ctx, q.rdb, []string{keyScheduled, keyReady}, time.Now().UnixMilli()).Int()
+}
+
+// requeueExpired mengembalikan job milik worker yang mati (lease habis) ke antrian
+func (q *Queue) requeueExpired(ctx context.Context) (int, error) {
+	ids, err := q.rdb.LRange(ctx, keyProcessing, 0, -1).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	requeued := 0
+	for _, id := range ids {
+		moved, err := requeueScript.Run(ctx, q.rdb, []string{keyLease(id), keyProcessing, keyReady}, id).Int()
+		if err != nil {
+			return requeued, err
+		}
+
+		requeued += moved
+	}
+
+	return requeued, nil
+}
+
+func (q *Queue) Stats(ctx context.Context) (Stats, error) {
+	var stats Stats
+
+	pipe := q.rdb.Pipeline()
+	ready := pipe.LLen(ctx, keyReady)
+	scheduled := pipe.ZCard(ctx, keyScheduled)
+	processing := pipe.LLen(ctx, keyProcessing)
+	dead := pipe.ZCard(ctx, keyDead)
+
+	_, err := pipe.Exec(ctx)
+	if err != nil {
+		return stats, err
+	}
+
+	stats.Ready = ready.Val()
+	stats.Scheduled = scheduled.Val()
+	stats.Processing = processing.Val()
+	stats.Dead = dead.Val()
+
+	return stats, nil
+}
+
+// DeadJobs mengembalikan job yang gagal permanen, paling baru lebih dulu
+func (q *Queue) DeadJobs(ctx context.Context, limit int) ([]Job, error) {
+	jobs := []Job{}
+
+	ids, err := q.rdb.ZRevRange(ctx, keyDead, 0, int64(limit)-1).Result()
+	if err != nil {
+		return jobs, err
+	}
+
+	for _, id := range ids {
+		job, err := q.get(ctx, id)
+		if errors.Is(err, ErrJobNotFound) {
+			continue
+		}
+		if err != nil {
+			return jobs, err
+		}
+
+		jobs = append(jobs, job)
+	}
+
+	return jobs, nil
+}
+
+// RetryDead menjalankan ulang job dari dead letter queue dengan jatah attempt baru
+func (q *Queue) RetryDead(ctx context.Context, id string) (Job, error) {
+	removed, err := q.rdb.ZRem(ctx, keyDead, id).Result()
+	if err != nil {
+		return Job{}, err
+	}
+
+	if removed == 0 {
+		return Job{}, ErrJobNotFound
+	}
+
+	job, err := q.get(ctx, id)
+	if err != nil {
+		return job, err
+	}
+
+	job.Attempts = 0
+	job.FailedAt = nil
+	job.RunAt = time.Now()
+
+	_, err = q.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		err := q.save(ctx, pipe, job)
+		if err != nil {
+			return err
+		}
+
+		pipe.LPush(ctx, keyReady, job.ID)
+		return nil
+	})
+
+	return job, err
+}
+
+func (q *Queue) get(ctx context.Context, id string) (Job, error) {
+	var job Job
+
+	encoded, err := q.rdb.Get(ctx, keyJob(id)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return job, ErrJobNotFound
+	}
+	if err != nil {
+		return job, err
+	}
+
+	err = json.Unmarshal(encoded, &job)
+	return job, err
+}
+
+// save menyimpan job tanpa ttl, termasuk menghapus ttl job dari dead letter queue
+func (q *Queue) save(ctx context.Context, cmd redis.Cmdable, job Job) error {
+	encoded, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	return cmd.Set(ctx, keyJob(job.ID), encoded, 0).Err()
+}
+
+func randomID() (string, error) {
+	random := make([]byte, 12)
+	_, err := rand.Read(random)
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.FormatInt(time.Now().Unix(), 36) + "-" + hex.EncodeToString(random), nil
+}
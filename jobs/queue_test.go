@@ -0,0 +1,90 @@
+package jobs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestQueue(t *testing.T) (*Queue, *miniredis.Miniredis) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	return NewQueue(rdb, QueueConfig{VisibilityTimeout: time.Minute, MaxAttempts: 3}), server
+}
+
+// job milik worker yang mati harus kembali ke antrian setelah lease habis,
+// tapi tidak boleh diambil alih selama lease masih ada
+func TestQueueRequeuesExpiredLease(t *testing.T) {
+	queue, server := newTestQueue(t)
+	ctx := context.Background()
+
+	enqueued, err := queue.Enqueue(ctx, "test", map[string]int{"n": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	job, ok, err := queue.fetch(ctx, 0)
+	if err != nil || !ok {
+		t.Fatalf("fetch() = %v, %v", ok, err)
+	}
+	if job.ID != enqueued.ID || job.Attempts != 1 {
+		t.Fatalf("fetch() job = %+v, want id %s attempts 1", job, enqueued.ID)
+	}
+
+	if !server.Exists(keyLease(job.ID)) {
+		t.Fatal("fetch() did not set a lease")
+	}
+
+	steps := []struct {
+		name    string
+		advance time.Duration
+		want    int
+	}{
+		{"lease masih berlaku", time.Second * 30, 0},
+		{"lease habis", time.Second * 31, 1},
+		{"sudah dikembalikan", 0, 0},
+	}
+
+	for _, step := range steps {
+		server.FastForward(step.advance)
+
+		requeued, err := queue.requeueExpired(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if requeued != step.want {
+			t.Errorf("%s: requeueExpired() = %d, want %d", step.name, requeued, step.want)
+		}
+	}
+
+	// dijalankan ulang sebagai percobaan kedua
+	job, ok, err = queue.fetch(ctx, 0)
+	if err != nil || !ok {
+		t.Fatalf("fetch() after requeue = %v, %v", ok, err)
+	}
+	if job.ID != enqueued.ID || job.Attempts != 2 {
+		t.Errorf("fetch() after requeue job = %+v, want id %s attempts 2", job, enqueued.ID)
+	}
+}
+
+func TestQueueRejectsDuplicateID(t *testing.T) {
+	queue, _ := newTestQueue(t)
+	ctx := context.Background()
+
+	_, err := queue.Enqueue(ctx, "test", nil, ID("same"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = queue.Enqueue(ctx, "test", nil, ID("same"))
+	if err != ErrDuplicateJob {
+		t.Fatalf("Enqueue() = %v, want ErrDuplicateJob", err)
+	}
+}
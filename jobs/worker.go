@@ -0,0 +1,215 @@
+package jobs
+
+import (
+	"auth-gorm-echo/metrics"
+	"auth-gorm-echo/tracing"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+type WorkerConfig struct {
+	Concurrency int
+	// lama fetch menunggu job sebelum mengecek apakah worker dihentikan
+	PollTimeout time.Duration
+	// jeda retry: BaseBackoff * 2^(attempt-1), maksimal MaxBackoff
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+}
+
+type handlerFunc func(ctx context.Context, payload json.RawMessage) error
+
+type Worker struct {
+	queue    *Queue
+	config   WorkerConfig
+	logger   *slog.Logger
+	handlers map[string]handlerFunc
+}
+
+func NewWorker(queue *Queue, config WorkerConfig, logger *slog.Logger) *Worker {
+	return &Worker{queue: queue, config: config, logger: logger, handlers: map[string]handlerFunc{}}
+}
+
+// Handle mendaftarkan handler untuk satu tipe job, payload otomatis di-decode ke T
+func Handle[T any](w *Worker, jobType Type[T], handler func(ctx context.Context, payload T) error) {
+	w.handlers[jobType.Name] = func(ctx context.Context, encoded json.RawMessage) error {
+		var payload T
+
+		err := json.Unmarshal(encoded, &payload)
+		if err != nil {
+			return fmt.Errorf("decode payload: %w", err)
+		}
+
+		return handler(ctx, payload)
+	}
+}
+
+// Run menjalankan worker sampai ctx dibatalkan, job yang sedang berjalan
+// ditunggu sampai selesai sebelum Run kembali
+func (w *Worker) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		w.maintain(ctx)
+	}()
+
+	for i := 0; i < w.config.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.poll(ctx)
+		}()
+	}
+
+	wg.Wait()
+}
+
+// maintain memindahkan job terjadwal yang sudah waktunya dan mengambil alih
+// job milik worker yang mati
+func (w *Worker) maintain(ctx context.Context) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		_, err := w.queue.promoteDue(ctx)
+		if err != nil && ctx.Err() == nil {
+			w.logger.ErrorContext(ctx, "failed to promote scheduled jobs", "error", err)
+		}
+
+		requeued, err := w.queue.requeueExpired(ctx)
+		if err != nil && ctx.Err() == nil {
+			w.logger.ErrorContext(ctx, "failed to requeue expired jobs", "error", err)
+		}
+		if requeued > 0 {
+			w.logger.WarnContext(ctx, "requeued jobs with expired lease", "count", requeued)
+		}
+	}
+}
+
+func (w *Worker) poll(ctx context.Context) {
+	for ctx.Err() == nil {
+		// fetch tidak memakai ctx supaya job yang sudah diambil tidak setengah jalan
+		job, ok, err := w.queue.fetch(context.WithoutCancel(ctx), w.config.PollTimeout)
+		if err != nil {
+			w.logger.ErrorContext(ctx, "failed to fetch job", "error", err)
+			time.Sleep(w.config.PollTimeout)
+			continue
+		}
+
+		if !ok {
+			continue
+		}
+
+		w.process(context.WithoutCancel(ctx), job)
+	}
+}
+
+func (w *Worker) process(ctx context.Context, job Job) {
+	ctx, span := tracing.Start(ctx, "jobs."+job.Type)
+	defer span.End()
+
+	logger := w.logger.With("job_id", job.ID, "job_type", job.Type, "attempt", job.Attempts)
+
+	// job dijalankan ulang setelah lease habis berkali-kali (worker crash saat mengerjakannya)
+	if job.Attempts > job.MaxAttempts {
+		w.bury(ctx, logger, job, errors.New("Lease expired after the last attempt"))
+		return
+	}
+
+	handler, ok := w.handlers[job.Type]
+	if !ok {
+		w.bury(ctx, logger, job, fmt.Errorf("No handler registered for job type %q", job.Type))
+		return
+	}
+
+	// job harus selesai sebelum lease habis, kalau tidak worker lain akan mengulangnya
+	jobCtx, cancel := context.WithTimeout(ctx, w.queue.config.VisibilityTimeout)
+	defer cancel()
+
+	jobCtx = context.WithValue(jobCtx, attemptKey{}, job.Attempts)
+
+	start := time.Now()
+	err := run(jobCtx, handler, job.Payload)
+	metrics.JobDuration.WithLabelValues(job.Type).Observe(time.Since(start).Seconds())
+
+	if err != nil {
+		tracing.RecordError(span, err)
+		w.fail(ctx, logger, job, err)
+		return
+	}
+
+	err = w.queue.complete(ctx, job)
+	if err != nil {
+		logger.ErrorContext(ctx, "failed to mark job as completed", "error", err)
+		return
+	}
+
+	metrics.JobsProcessed.WithLabelValues(job.Type, "success").Inc()
+	logger.DebugContext(ctx, "job completed", "duration_ms", time.Since(start).Milliseconds())
+}
+
+// run memanggil handler, panic dianggap sebagai job yang gagal
+func run(ctx context.Context, handler handlerFunc, payload json.RawMessage) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("panic: %v", recovered)
+		}
+	}()
+
+	return handler(ctx, payload)
+}
+
+func (w *Worker) fail(ctx context.Context, logger *slog.Logger, job Job, jobErr error) {
+	if job.Attempts >= job.MaxAttempts {
+		w.bury(ctx, logger, job, jobErr)
+		return
+	}
+
+	delay := w.backoff(job.Attempts)
+
+	err := w.queue.retry(ctx, job, time.Now().Add(delay), jobErr)
+	if err != nil {
+		logger.ErrorContext(ctx, "failed to schedule job retry", "error", err)
+		return
+	}
+
+	metrics.JobsProcessed.WithLabelValues(job.Type, "retry").Inc()
+	logger.WarnContext(ctx, "job failed, will retry", "retry_in", delay.String(), "error", jobErr)
+}
+
+func (w *Worker) bury(ctx context.Context, logger *slog.Logger, job Job, jobErr error) {
+	err := w.queue.bury(ctx, job, jobErr)
+	if err != nil {
+		logger.ErrorContext(ctx, "failed to move job to dead letter queue", "error", err)
+		return
+	}
+
+	metrics.JobsProcessed.WithLabelValues(job.Type, "dead").Inc()
+	logger.ErrorContext(ctx, "job moved to dead letter queue", "error", jobErr)
+}
+
+// backoff eksponensial dengan jitter supaya retry tidak serentak
+func (w *Worker) backoff(attempt int) time.Duration {
+	delay := float64(w.config.BaseBackoff) * math.Pow(2, float64(attempt-1))
+	if delay > float64(w.config.MaxBackoff) {
+		delay = float64(w.config.MaxBackoff)
+	}
+
+	jitter := rand.Float64() * delay * 0.2
+
+	return time.Duration(delay + jitter)
+}
@@ -0,0 +1,76 @@
+package logging
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// RequestIDMiddleware memakai X-Request-ID dari client kalau ada, kalau tidak dibuat
+// baru. ID dikirim balik di header response dan disimpan di context request.
+func RequestIDMiddleware() echo.MiddlewareFunc {
+	return middleware.RequestIDWithConfig(middleware.RequestIDConfig{
+		RequestIDHandler: func(c echo.Context, requestID string) {
+			if len(requestID) > 128 {
+				requestID = requestID[:128]
+				c.Response().Header().Set(echo.HeaderXRequestID, requestID)
+			}
+
+			c.SetRequest(c.Request().WithContext(WithRequestID(c.Request().Context(), requestID)))
+		},
+	})
+}
+
+// SetUserID dipanggil auth middleware setelah currentUser diketahui
+func SetUserID(c echo.Context, userID int) {
+	c.SetRequest(c.Request().WithContext(WithUserID(c.Request().Context(), userID)))
+}
+
+// Middleware menulis satu baris log untuk setiap request. Request ke
+// quietPaths (health check, metrics) hanya ditulis di level debug.
+func Middleware(logger *slog.Logger, quietPaths ...string) echo.MiddlewareFunc {
+	quiet := make(map[string]bool, len(quietPaths))
+	for _, path := range quietPaths {
+		quiet[path] = true
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+
+			err := next(c)
+			if err != nil {
+				c.Error(err)
+			}
+
+			req := c.Request()
+			status := c.Response().Status
+
+			level := slog.LevelInfo
+			if status >= 500 {
+				level = slog.LevelError
+			} else if quiet[c.Path()] {
+				level = slog.LevelDebug
+			}
+
+			attrs := []slog.Attr{
+				slog.String("method", req.Method),
+				slog.String("path", c.Path()),
+				slog.String("uri", req.RequestURI),
+				slog.Int("status", status),
+				slog.Int64("latency_ms", time.Since(start).Milliseconds()),
+				slog.String("remote_ip", c.RealIP()),
+				slog.Int64("bytes_out", c.Response().Size),
+			}
+			if err != nil {
+				attrs = append(attrs, slog.String("error", err.Error()))
+			}
+
+			logger.LogAttrs(req.Context(), level, "request", attrs...)
+
+			return nil
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package logging
+
+import (
+	"auth-gorm-echo/config"
+	"context"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+type contextKey int
+
+const (
+	requestIDKey contextKey = iota
+	userIDKey
+)
+
+// New membuat logger JSON ke stdout, level diatur lewat LOG_LEVEL
+// (debug, info, warn, error). Request ID dan user ID yang tersimpan di
+// context otomatis ikut ditulis saat memakai method *Context.
+func New() *slog.Logger {
+	var level slog.Level
+
+	err := level.UnmarshalText([]byte(strings.ToUpper(config.Getenv("LOG_LEVEL", "info"))))
+	if err != nil {
+		level = slog.LevelInfo
+	}
+
+	handler := slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: level})
+
+	return slog.New(contextHandler{handler})
+}
+
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
+
+func WithUserID(ctx context.Context, userID int) context.Context {
+	return context.WithValue(ctx, userIDKey, userID)
+}
+
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if requestID := RequestID(ctx); requestID != "" {
+		record.AddAttrs(slog.String("request_id", requestID))
+	}
+
+	if userID, ok := ctx.Value(userIDKey).(int); ok {
+		record.AddAttrs(slog.Int("user_id", userID))
+	}
+
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,66 @@
+package mailer
+
+import (
+	"fmt"
+	"log"
+	"net/smtp"
+	"strings"
+
+	"auth-gorm-echo/config"
+)
+
+type Mailer interface {
+	Send(to string, subject string, body string) error
+}
+
+type smtpMailer struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+type logMailer struct {
+}
+
+// NewMailer memakai SMTP kalau SMTP_HOST diisi, kalau tidak email hanya
+// ditulis ke log (berguna untuk development)
+func NewMailer() Mailer {
+	host := config.Getenv("SMTP_HOST", "")
+	if host == "" {
+		return &logMailer{}
+	}
+
+	port := config.Getenv("SMTP_PORT", "587")
+	username := config.Getenv("SMTP_USERNAME", "")
+	password := config.Getenv("SMTP_PASSWORD", "")
+
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return &smtpMailer{
+		addr: fmt.Sprintf("%s:%s", host, port),
+		from: config.Getenv("SMTP_FROM", "no-reply@crowdfunding.local"),
+		auth: auth,
+	}
+}
+
+func (m *smtpMailer) Send(to string, subject string, body string) error {
+	message := strings.Join([]string{
+		"From: " + m.from,
+		"To: " + to,
+		"Subject: " + subject,
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=\"utf-8\"",
+		"",
+		body,
+	}, "\r\n")
+
+	return smtp.SendMail(m.addr, m.auth, m.from, []string{to}, []byte(message))
+}
+
+func (m *logMailer) Send(to string, subject string, body string) error {
+	log.Printf("mailer: to=%s subject=%q\n%s", to, subject, body)
+	return nil
+}
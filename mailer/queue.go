@@ -0,0 +1,37 @@
+package mailer
+
+import (
+	"auth-gorm-echo/jobs"
+	"context"
+)
+
+// Email adalah payload job email.send
+type Email struct {
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+var SendEmailJob = jobs.NewType[Email]("email.send")
+
+// queueMailer memasukkan email ke antrian job supaya request tidak menunggu
+// SMTP dan email tidak hilang kalau server mati sebelum terkirim
+type queueMailer struct {
+	queue *jobs.Queue
+}
+
+func NewQueueMailer(queue *jobs.Queue) *queueMailer {
+	return &queueMailer{queue}
+}
+
+func (m *queueMailer) Send(to string, subject string, body string) error {
+	_, err := SendEmailJob.Enqueue(context.Background(), m.queue, Email{To: to, Subject: subject, Body: body})
+	return err
+}
+
+// SendEmailHandler mengirim email dari job lewat mailer sebenarnya (SMTP / log)
+func SendEmailHandler(mailer Mailer) func(ctx context.Context, email Email) error {
+	return func(ctx context.Context, email Email) error {
+		return mailer.Send(email.To, email.Subject, email.Body)
+	}
+}
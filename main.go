@@ -1,128 +1,255 @@
 package main
 
 import (
-	"auth-gorm-echo/auth"
-	"auth-gorm-echo/campaign"
 	"auth-gorm-echo/config"
+	"auth-gorm-echo/docs"
 	"auth-gorm-echo/handler"
 	"auth-gorm-echo/helper"
-	"auth-gorm-echo/user"
+	"auth-gorm-echo/i18n"
+	"auth-gorm-echo/logging"
+	"auth-gorm-echo/metrics"
+	"auth-gorm-echo/oauth"
+	"auth-gorm-echo/ratelimit"
+	"auth-gorm-echo/validation"
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
-	"strings"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
-	// "fmt"
-	// "net/http"
-
-	"github.com/go-playground/validator/v10"
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/labstack/echo/otelecho"
 )
 
-// Custom Error
-type CustomValidator struct {
-	validator *validator.Validate
-}
+const usage = `usage: server <command> [arguments]
 
-func (cv *CustomValidator) Validate(i interface{}) error {
-	return cv.validator.Struct(i)
-}
+commands:
+  serve [--addr :9000]                     start the HTTP server (default)
+  migrate up|down|status [n]               run database migrations
+  seed                                     load demo users, campaigns and images
+  user create --name --email --password [--occupation] [--admin]
+  campaign close-expired                   close campaigns whose deadline has passed
+  token issue --user-id <id>               issue a JWT for a user
+  worker                                   run background jobs without the HTTP server
+  docs check|print                         check the OpenAPI document against the router, or print it
+`
 
 func main() {
-	// Connect to database
-	config.DatabaseInit()
-	db := config.GetDB()
+	command := "serve"
+	args := []string{}
+
+	if len(os.Args) > 1 {
+		command = os.Args[1]
+		args = os.Args[2:]
+	}
+
+	switch command {
+	case "serve":
+		runServe(args)
+	case "migrate":
+		runMigrate(args)
+	case "seed":
+		runSeed(args)
+	case "user":
+		runUser(args)
+	case "campaign":
+		runCampaign(args)
+	case "token":
+		runToken(args)
+	case "worker":
+		runWorker(args)
+	case "docs":
+		runDocs(args)
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
+
+func serve(app *app, addr string) {
+	logger := app.logger
 
-	dbGorm, err := db.DB()
+	err := metrics.RegisterBusinessCollector(app.db)
 	if err != nil {
-		panic(err)
+		logger.Error("failed to register business metrics", "error", err)
+		os.Exit(1)
 	}
-	dbGorm.Ping()
 
-	// initialize redis
-	config.RedisInit()
+	router, healthHandler := newRouter(app)
 
-	userRepository := user.NewRepository(db)
-	campaignRepository := campaign.NewRepository(db)
+	err = docs.Verify(router.Routes(), docs.Operations)
+	if err != nil {
+		logger.Warn("api docs are out of date", "error", err)
+	}
 
-	userService := user.NewService(userRepository)
-	campaignService := campaign.NewService(campaignRepository)
-	authService := auth.NewService()
+	// start server, shutdown saat menerima SIGINT / SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	userHandler := handler.NewUserHandler(userService, authService)
-	campaignHandler := handler.NewCampaignHandler(campaignService)
-	
-	router := echo.New()
-	router.Validator = &CustomValidator{validator: validator.New()}
+	// worker ikut berjalan di proses yang sama kecuali dijalankan terpisah (`server worker`)
+	// hub berhenti saat ctx dibatalkan dan menutup semua stream sehingga
+	// Shutdown di bawah tidak menunggu koneksi stream sampai timeout
+	go app.progressHub.Run(ctx)
 
-	// access images
-	router.Static("/images", "./images")
+	waitWorker := func() {}
+	if config.Getenv("WORKER_IN_PROCESS", "true") == "true" {
+		waitWorker = startWorker(ctx, app)
+	}
 
-	// Router
-	api := router.Group("/api/v1")
+	go func() {
+		logger.Info("http server started", "addr", addr)
+
+		err := router.Start(addr)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("http server failed", "error", err)
+			os.Exit(1)
+		}
+	}()
 
-	api.POST("/users", userHandler.RegisterUser)
-	api.POST("/sessions", userHandler.Login)
-	api.POST("/email_checkers", userHandler.CheckEmailAvailability)
+	<-ctx.Done()
+	stop()
 
-	api.GET("/campaigns", campaignHandler.GetCampaigns)
-	api.GET("/campaigns/:id", campaignHandler.GetCampaign)
+	logger.Info("shutting down, draining in-flight requests")
+	healthHandler.SetDraining()
 
-	api.Use(authMiddleware(authService, userService))
-	api.GET("/users/fetch", userHandler.FetchUser)
-	api.POST("/avatars", userHandler.UploadAvatar)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), config.GetenvDuration("SHUTDOWN_TIMEOUT", time.Second*20))
+	defer cancel()
 
-	api.POST("/campaigns", campaignHandler.CreateCampaign)
+	err = router.Shutdown(shutdownCtx)
+	if err != nil {
+		logger.Error("http server shutdown failed", "error", err)
+	}
 
+	waitWorker()
 
-	router.Logger.Fatal(router.Start(":9000"))
+	app.close(shutdownCtx)
 }
 
-// middleware
-func authMiddleware(authService auth.Service, userService user.Service) echo.MiddlewareFunc {
-	return func(next echo.HandlerFunc) echo.HandlerFunc {
-		return func(c echo.Context) error {
-			authHeader := c.Request().Header.Get("Authorization")
-
-			if !strings.Contains(authHeader, "Bearer") {
-				response := helper.APIResponse("Unauthorized", http.StatusUnauthorized, "error", nil)
-				return c.JSON(http.StatusUnauthorized, response)
-			}
-
-			tokenString := ""
-			arrayToken := strings.Split(authHeader, " ")
-			if len(arrayToken) == 2 {
-				tokenString = arrayToken[1]
-			}
-
-			token, err := authService.ValidateToken(tokenString)
-			if err != nil {
-				response := helper.APIResponse("Unauthorized", http.StatusUnauthorized, "error", nil)
-				return c.JSON(http.StatusUnauthorized, response)
-			}
-
-			claim, ok := token.Claims.(jwt.MapClaims)
-
-			if !ok || !token.Valid {
-				response := helper.APIResponse("Unauthorized", http.StatusUnauthorized, "error", nil)
-				return c.JSON(http.StatusUnauthorized, response)
-			}
-
-			userID := int(claim["user_id"].(float64))
-
-			user, err := userService.GetUserByID(userID)
-			if err != nil {
-				response := helper.APIResponse("Unauthorized", http.StatusUnauthorized, "error", nil)
-				return c.JSON(http.StatusUnauthorized, response)
-			}
-
-			c.Set("currentUser", user)
-			return next(c)
+// newRouter mendaftarkan middleware dan semua route. Tidak membuka koneksi
+// apapun sehingga bisa dipakai `docs check` tanpa database.
+func newRouter(app *app) (*echo.Echo, interface{ SetDraining() }) {
+	userService := app.userService
+	campaignService := app.campaignService
+	authService := app.authService
+	apiKeyService := app.apiKeyService
+	store := app.store
+
+	logger := app.logger
+
+	userHandler := handler.NewUserHandler(userService, authService, oauth.NewProviders(), app.avatarUploadService, logger)
+	campaignHandler := handler.NewCampaignHandler(campaignService, app.campaignImageUploadService, logger)
+	streamHandler := handler.NewStreamHandler(campaignService, app.progressHub)
+	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyService)
+	webhookHandler := handler.NewWebhookHandler(app.webhookService)
+	pledgeHandler := handler.NewPledgeHandler(app.pledgeService)
+	jobHandler := handler.NewJobHandler(app.queue)
+	healthHandler := handler.NewHealthHandler(app.sqlDB, app.rdb, config.GetenvDuration("READINESS_TIMEOUT", time.Second*2))
+
+	router := echo.New()
+	router.HideBanner = true
+	router.HidePort = true
+	router.Validator = validation.New()
+	router.Binder = &validation.Binder{}
+	router.JSONSerializer = helper.JSONSerializer{}
+	router.HTTPErrorHandler = handler.HTTPErrorHandler
+	// X-Forwarded-For hanya dipercaya dari proxy di jaringan private, kalau
+	// tidak IP untuk rate limit dan login guard bisa dipalsukan client
+	router.IPExtractor = echo.ExtractIPFromXFFHeader()
+	router.Use(logging.RequestIDMiddleware())
+	router.Use(i18n.Middleware())
+	router.Use(metrics.Middleware())
+	router.Use(otelecho.Middleware("crowdfunding", otelecho.WithSkipper(func(c echo.Context) bool {
+		switch c.Path() {
+		case "/metrics", "/healthz", "/readyz":
+			return true
+		// koneksi stream bisa terbuka berjam-jam, span-nya tidak berguna
+		case "/api/v1/campaigns/:id/stream", "/api/v1/campaigns/:id/ws":
+			return true
 		}
+
+		return false
+	})))
+
+	router.Use(logging.Middleware(logger, "/metrics", "/healthz", "/readyz"))
+
+	router.GET("/metrics", echo.WrapHandler(metrics.Handler()))
+
+	// dokumentasi API, lihat package docs
+	router.GET("/api/v1/openapi.json", docs.SpecHandler(docs.Build(docs.Operations, apiKeyScopes)))
+	router.GET("/docs", docs.UIHandler)
+
+	// health check untuk orchestrator / load balancer
+	router.GET("/healthz", healthHandler.Liveness)
+	router.GET("/readyz", healthHandler.Readiness)
+
+	// access images, hanya dibutuhkan kalau file disimpan di disk lokal
+	if localStore, ok := store.(interface{ Dir() string }); ok {
+		// sama seperti router.Static tapi dengan header cache immutable
+		images := echo.StaticDirectoryHandler(echo.MustSubFS(router.Filesystem, localStore.Dir()), false)
+		router.GET("/images*", images, handler.ImmutableCache)
 	}
+
+	// rate limit per route, kuota dibagi antar instance lewat redis
+	limiter := ratelimit.NewLimiter(app.rdb, logger)
+	registerLimit := limiter.Middleware(ratelimit.Policy{Name: "register", Limit: config.GetenvInt("RATE_LIMIT_REGISTER", 5), Window: time.Hour, Key: ratelimit.ByIP})
+	loginLimit := limiter.Middleware(ratelimit.Policy{Name: "login", Limit: config.GetenvInt("RATE_LIMIT_LOGIN", 20), Window: time.Minute, Key: ratelimit.ByIP})
+	emailCheckLimit := limiter.Middleware(ratelimit.Policy{Name: "email_check", Limit: config.GetenvInt("RATE_LIMIT_EMAIL_CHECK", 10), Window: time.Minute, Key: ratelimit.ByIP})
+	streamLimit := limiter.Middleware(ratelimit.Policy{Name: "stream", Limit: config.GetenvInt("RATE_LIMIT_STREAM", 30), Window: time.Minute, Key: ratelimit.ByIP})
+	createCampaignLimit := limiter.Middleware(ratelimit.Policy{Name: "create_campaign", Limit: config.GetenvInt("RATE_LIMIT_CREATE_CAMPAIGN", 20), Window: time.Hour, Key: ratelimit.ByPrincipal})
+
+	// Router
+	api := router.Group("/api/v1")
+
+	api.POST("/users", userHandler.RegisterUser, registerLimit)
+	api.POST("/sessions", userHandler.Login, loginLimit)
+	api.POST("/sessions/2fa", userHandler.VerifyTwoFactorLogin, loginLimit)
+	api.POST("/sessions/oauth/:provider", userHandler.OAuthLogin, loginLimit)
+	api.POST("/email_checkers", userHandler.CheckEmailAvailability, emailCheckLimit)
+
+	api.GET("/campaigns", campaignHandler.GetCampaigns, optionalAuthMiddleware(authService, userService, apiKeyService))
+	api.GET("/campaigns/:id", campaignHandler.GetCampaign, optionalAuthMiddleware(authService, userService, apiKeyService))
+	api.GET("/campaigns/:id/stream", streamHandler.StreamProgress, streamLimit)
+	api.GET("/campaigns/:id/ws", streamHandler.StreamProgressWebSocket, streamLimit)
+
+	api.Use(authMiddleware(authService, userService, apiKeyService))
+	api.GET("/users/fetch", userHandler.FetchUser)
+	api.POST("/avatars", userHandler.UploadAvatar, middleware.BodyLimit("6M"))
+	api.POST("/users/me/2fa", userHandler.SetupTwoFactor)
+	api.POST("/users/me/2fa/confirm", userHandler.ConfirmTwoFactor)
+	api.DELETE("/users/me/2fa", userHandler.DisableTwoFactor)
+	api.POST("/users/me/api_keys", apiKeyHandler.CreateAPIKey)
+	api.GET("/users/me/api_keys", apiKeyHandler.GetAPIKeys)
+	api.DELETE("/users/me/api_keys/:id", apiKeyHandler.DeleteAPIKey)
+	api.POST("/users/me/webhooks", webhookHandler.CreateEndpoint)
+	api.GET("/users/me/webhooks", webhookHandler.GetEndpoints)
+	api.DELETE("/users/me/webhooks/:id", webhookHandler.DeleteEndpoint)
+	api.POST("/users/me/webhooks/:id/test", webhookHandler.SendTestEvent)
+	api.GET("/users/me/webhooks/:id/deliveries", webhookHandler.GetDeliveries)
+
+	api.POST("/campaigns", campaignHandler.CreateCampaign, createCampaignLimit)
+	api.POST("/campaign-images", campaignHandler.UploadImage, middleware.BodyLimit("11M"))
+	api.POST("/campaigns/:id/pledges", pledgeHandler.CreatePledge)
+	api.GET("/campaigns/:id/pledges", pledgeHandler.GetCampaignPledges)
+
+	admin := api.Group("/admin", adminMiddleware)
+	admin.GET("/jobs", jobHandler.GetStats)
+	admin.GET("/jobs/dead", jobHandler.GetDeadJobs)
+	admin.POST("/jobs/dead/:id/retry", jobHandler.RetryDeadJob)
+	// belum ada payment gateway, pembayaran dikonfirmasi admin
+	admin.POST("/pledges/:id/paid", pledgeHandler.MarkPledgePaid)
+	admin.POST("/pledges/:id/refund", pledgeHandler.RefundPledge)
+
+	return router, healthHandler
 }
 
 // input dari user
 // handler mapping input dari user ke struct input
 // service mapping ke struct User
 // repository save struct User ke db
-// db
\ No newline at end of file
+// db
@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"gorm.io/gorm"
+)
+
+var (
+	openCampaignsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "open_campaigns"),
+		"Number of campaigns that are still open.", nil, nil)
+
+	amountRaisedDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "amount_raised"),
+		"Total amount (IDR) raised across all campaigns.", nil, nil)
+)
+
+// businessCollector menghitung gauge dari database setiap kali /metrics di-scrape
+type businessCollector struct {
+	db      *gorm.DB
+	timeout time.Duration
+}
+
+// RegisterBusinessCollector dipanggil di main.go setelah database terhubung
+func RegisterBusinessCollector(db *gorm.DB) error {
+	return Registry.Register(&businessCollector{db, time.Second * 2})
+}
+
+func (c *businessCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- openCampaignsDesc
+	ch <- amountRaisedDesc
+}
+
+func (c *businessCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	var result struct {
+		OpenCampaigns int64
+		AmountRaised  int64
+	}
+
+	err := c.db.WithContext(ctx).
+		Table("campaigns").
+		Select("COUNT(*) FILTER (WHERE closed_at IS NULL) AS open_campaigns, COALESCE(SUM(current_amount), 0) AS amount_raised").
+		Scan(&result).Error
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(openCampaignsDesc, err)
+		ch <- prometheus.NewInvalidMetric(amountRaisedDesc, err)
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(openCampaignsDesc, prometheus.GaugeValue, float64(result.OpenCampaigns))
+	ch <- prometheus.MustNewConstMetric(amountRaisedDesc, prometheus.GaugeValue, float64(result.AmountRaised))
+}
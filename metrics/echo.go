@@ -0,0 +1,43 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Middleware mencatat jumlah dan latency request per route. Label route memakai
+// pola route (contoh /api/v1/campaigns/:id) supaya jumlah series tidak meledak.
+func Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+
+			err := next(c)
+			if err != nil {
+				c.Error(err)
+			}
+
+			route := c.Path()
+			if route == "" {
+				route = "unmatched"
+			}
+
+			method := c.Request().Method
+			status := strconv.Itoa(c.Response().Status)
+
+			HTTPRequests.WithLabelValues(method, route, status).Inc()
+			HTTPRequestDuration.WithLabelValues(method, route).Observe(time.Since(start).Seconds())
+
+			// error sudah ditangani c.Error di atas
+			return nil
+		}
+	}
+}
+
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
@@ -0,0 +1,55 @@
+package metrics
+
+import (
+	"auth-gorm-echo/instrument"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const startTimeKey = "metrics:start_time"
+
+// GormPlugin mencatat durasi setiap query GORM lewat callback
+type GormPlugin struct{}
+
+func (p GormPlugin) Name() string {
+	return "metrics"
+}
+
+func (p GormPlugin) Initialize(db *gorm.DB) error {
+	return instrument.RegisterGormCallbacks(db, "metrics", instrument.GormCallbacks{
+		Before: func(string) func(*gorm.DB) {
+			return before
+		},
+		After: after,
+	})
+}
+
+func before(db *gorm.DB) {
+	db.InstanceSet(startTimeKey, time.Now())
+}
+
+func after(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		value, ok := db.InstanceGet(startTimeKey)
+		if !ok {
+			return
+		}
+
+		start, ok := value.(time.Time)
+		if !ok {
+			return
+		}
+
+		table := db.Statement.Table
+		if table == "" {
+			table = "unknown"
+		}
+
+		DBQueryDuration.WithLabelValues(operation, table).Observe(time.Since(start).Seconds())
+
+		if db.Error != nil && db.Error != gorm.ErrRecordNotFound {
+			DBQueryErrors.WithLabelValues(operation, table).Inc()
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const namespace = "crowdfunding"
+
+var (
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "Number of HTTP requests by method, route and status code.",
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method and route.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	DBQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "Database query latency by operation and table.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation", "table"})
+
+	DBQueryErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "db_query_errors_total",
+		Help:      "Number of failed database queries by operation and table.",
+	}, []string{"operation", "table"})
+
+	RedisCommands = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "redis_commands_total",
+		Help:      "Number of Redis commands by command and result.",
+	}, []string{"command", "status"})
+
+	RedisCommandDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "redis_command_duration_seconds",
+		Help:      "Redis command latency by command.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5},
+	}, []string{"command"})
+
+	CampaignsCreated = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "campaigns_created_total",
+		Help:      "Number of campaigns created.",
+	})
+
+	PledgesPaid = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "pledges_paid_total",
+		Help:      "Number of pledges that have been paid.",
+	})
+
+	PledgeAmountPaid = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "pledge_amount_paid_total",
+		Help:      "Total amount (IDR) of paid pledges.",
+	})
+
+	FailedLogins = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "failed_logins_total",
+		Help:      "Number of failed login attempts by reason.",
+	}, []string{"reason"})
+
+	JobsProcessed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "jobs_processed_total",
+		Help:      "Number of background jobs processed by type and result (success, retry, dead).",
+	}, []string{"type", "result"})
+
+	JobDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "job_duration_seconds",
+		Help:      "Background job latency by type.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"type"})
+
+	OutboxEvents = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "outbox_events_total",
+		Help:      "Number of outbox events handled by the relay by type and result (published, retry).",
+	}, []string{"type", "result"})
+
+	CacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_requests_total",
+		Help:      "Number of cache lookups by cache and result (hit, miss, error).",
+	}, []string{"cache", "result"})
+
+	StreamConnections = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "stream_connections",
+		Help:      "Number of open campaign progress streams by transport (sse, websocket).",
+	}, []string{"transport"})
+)
+
+var Registry = prometheus.NewRegistry()
+
+func init() {
+	Registry.MustRegister(
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+		HTTPRequests,
+		HTTPRequestDuration,
+		DBQueryDuration,
+		DBQueryErrors,
+		RedisCommands,
+		RedisCommandDuration,
+		CampaignsCreated,
+		PledgesPaid,
+		PledgeAmountPaid,
+		FailedLogins,
+		CacheRequests,
+		JobsProcessed,
+		JobDuration,
+		OutboxEvents,
+		StreamConnections,
+	)
+}
+
+// RecordPledgePaid dipanggil setelah transaksi settle pledge di-commit
+func RecordPledgePaid(amount int) {
+	PledgesPaid.Inc()
+	PledgeAmountPaid.Add(float64(amount))
+}
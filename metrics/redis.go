@@ -0,0 +1,32 @@
+package metrics
+
+import (
+	"auth-gorm-echo/instrument"
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisHook mencatat jumlah dan latency command redis
+var RedisHook = instrument.RedisHook{
+	Start: func(ctx context.Context, command string) (context.Context, func(error)) {
+		start := time.Now()
+
+		return ctx, func(err error) {
+			observeRedis(command, time.Since(start), err)
+		}
+	},
+}
+
+func observeRedis(command string, duration time.Duration, err error) {
+	status := "ok"
+
+	// redis.Nil artinya key tidak ada, bukan error
+	if err != nil && err != redis.Nil {
+		status = "error"
+	}
+
+	RedisCommands.WithLabelValues(command, status).Inc()
+	RedisCommandDuration.WithLabelValues(command).Observe(duration.Seconds())
+}
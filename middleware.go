@@ -0,0 +1,147 @@
+package main
+
+import (
+	"auth-gorm-echo/apikey"
+	"auth-gorm-echo/auth"
+	"auth-gorm-echo/helper"
+	"auth-gorm-echo/logging"
+	"auth-gorm-echo/user"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+)
+
+// scope yang dibutuhkan API key per route, route yang tidak terdaftar di sini
+// hanya bisa diakses dengan JWT
+var apiKeyScopes = map[string]string{
+	"GET /api/v1/campaigns":     apikey.ScopeReadCampaigns,
+	"GET /api/v1/campaigns/:id": apikey.ScopeReadCampaigns,
+	"POST /api/v1/campaigns":    apikey.ScopeWriteCampaigns,
+
+	"GET /api/v1/campaigns/:id/pledges": apikey.ScopeReadTransactions,
+}
+
+// middleware
+func authMiddleware(authService auth.Service, userService user.Service, apiKeyService apikey.Service) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			user, errorCode, err := authenticate(c, authService, userService, apiKeyService)
+			if err != nil {
+				return err
+			}
+			if errorCode != "" {
+				return unauthorizedResponse(c, errorCode)
+			}
+
+			c.Set("currentUser", user)
+			logging.SetUserID(c, user.ID)
+
+			return next(c)
+		}
+	}
+}
+
+// optionalAuthMiddleware dipakai di endpoint publik: tanpa header request tetap
+// diteruskan, tapi kalau header dikirim maka token harus valid
+func optionalAuthMiddleware(authService auth.Service, userService user.Service, apiKeyService apikey.Service) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if c.Request().Header.Get("Authorization") == "" {
+				return next(c)
+			}
+
+			user, errorCode, err := authenticate(c, authService, userService, apiKeyService)
+			if err != nil {
+				return err
+			}
+			if errorCode != "" {
+				return unauthorizedResponse(c, errorCode)
+			}
+
+			c.Set("currentUser", user)
+			logging.SetUserID(c, user.ID)
+
+			return next(c)
+		}
+	}
+}
+
+// adminMiddleware dipasang setelah authMiddleware, hanya user dengan role admin
+// yang boleh lewat
+func adminMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		currentUser, ok := c.Get("currentUser").(user.User)
+		if !ok || currentUser.Role != "admin" {
+			response := helper.APIErrorResponse("auth.admin_required", http.StatusForbidden, "auth.admin_required", nil)
+			return c.JSON(http.StatusForbidden, response)
+		}
+
+		return next(c)
+	}
+}
+
+func authenticate(c echo.Context, authService auth.Service, userService user.Service, apiKeyService apikey.Service) (user.User, string, error) {
+	scheme, credential, err := auth.ParseAuthorizationHeader(c.Request().Header.Get("Authorization"))
+	if err != nil {
+		return user.User{}, auth.ErrorCode(err), nil
+	}
+
+	userID := 0
+
+	if scheme == auth.SchemeAPIKey {
+		scope, ok := apiKeyScopes[c.Request().Method+" "+c.Path()]
+		if !ok {
+			return user.User{}, "auth.api_key_not_allowed", nil
+		}
+
+		apiKey, err := apiKeyService.Authenticate(c.Request().Context(), credential)
+		if err != nil {
+			if errors.Is(err, apikey.ErrExpiredAPIKey) {
+				return user.User{}, "auth.api_key_expired", nil
+			}
+			if errors.Is(err, apikey.ErrInvalidAPIKey) {
+				return user.User{}, "auth.invalid_api_key", nil
+			}
+
+			return user.User{}, "", err
+		}
+
+		if !apiKey.HasScope(scope) {
+			return user.User{}, "auth.insufficient_scope", nil
+		}
+
+		c.Set("apiKey", apiKey)
+		userID = apiKey.UserID
+	} else {
+		claims, err := authService.ValidateToken(credential)
+		if err != nil {
+			return user.User{}, auth.ErrorCode(err), nil
+		}
+
+		userID = claims.UserID
+	}
+
+	// hanya user yang tidak ada yang berarti token tidak valid, error database
+	// diteruskan ke HTTPErrorHandler supaya client mendapat 5xx
+	currentUser, err := userService.GetUserByID(c.Request().Context(), userID)
+	if errors.Is(err, user.ErrUserNotFound) {
+		return user.User{}, "auth.user_not_found", nil
+	}
+	if err != nil {
+		return user.User{}, "", err
+	}
+
+	return currentUser, "", nil
+}
+
+func unauthorizedResponse(c echo.Context, errorCode string) error {
+	// kredensial valid tapi tidak punya akses ke route ini
+	if errorCode == "auth.api_key_not_allowed" || errorCode == "auth.insufficient_scope" {
+		response := helper.APIErrorResponse(errorCode, http.StatusForbidden, errorCode, nil)
+		return c.JSON(http.StatusForbidden, response)
+	}
+
+	response := helper.APIErrorResponse(errorCode, http.StatusUnauthorized, errorCode, nil)
+	return c.JSON(http.StatusUnauthorized, response)
+}
@@ -0,0 +1,241 @@
+package migration
+
+import (
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+//go:embed sql/*.sql
+var files embed.FS
+
+// Migration adalah satu pasang file <version>_<name>.up.sql / .down.sql
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+type Status struct {
+	Migration Migration
+	AppliedAt *time.Time
+}
+
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+func NewMigrator(db *sql.DB) (*Migrator, error) {
+	migrations, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{db, migrations}, nil
+}
+
+// Up menjalankan migration yang belum dijalankan, steps 0 berarti semua
+func (m *Migrator) Up(steps int) ([]Migration, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+
+		if steps > 0 && len(done) == steps {
+			break
+		}
+
+		err = m.run(migration.Up, "INSERT INTO schema_migrations (version) VALUES ($1)", migration.Version)
+		if err != nil {
+			return done, errors.Wrapf(err, "migration %04d_%s up", migration.Version, migration.Name)
+		}
+
+		done = append(done, migration)
+	}
+
+	return done, nil
+}
+
+// Down me-rollback migration terakhir sebanyak steps
+func (m *Migrator) Down(steps int) ([]Migration, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+
+	for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		migration := m.migrations[i]
+
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+
+		err = m.run(migration.Down, "DELETE FROM schema_migrations WHERE version = $1", migration.Version)
+		if err != nil {
+			return done, errors.Wrapf(err, "migration %04d_%s down", migration.Version, migration.Name)
+		}
+
+		done = append(done, migration)
+	}
+
+	return done, nil
+}
+
+func (m *Migrator) Status() ([]Status, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []Status
+
+	for _, migration := range m.migrations {
+		status := Status{Migration: migration}
+
+		if appliedAt, ok := applied[migration.Version]; ok {
+			status.AppliedAt = &appliedAt
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// run menjalankan SQL migration dan mencatat versinya dalam satu transaksi
+func (m *Migrator) run(statements string, record string, version int) error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(statements)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	_, err = tx.Exec(record, version)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (m *Migrator) applied() (map[int]time.Time, error) {
+	_, err := m.db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INT PRIMARY KEY,
+		applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := m.db.Query("SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[int]time.Time{}
+
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+
+		err = rows.Scan(&version, &appliedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		applied[version] = appliedAt
+	}
+
+	return applied, rows.Err()
+}
+
+func load() ([]Migration, error) {
+	byVersion := map[int]*Migration{}
+
+	err := fs.WalkDir(files, "sql", func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+
+		name := strings.TrimPrefix(path, "sql/")
+
+		direction := ""
+		switch {
+		case strings.HasSuffix(name, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(name, ".down.sql"):
+			direction = "down"
+		default:
+			return fmt.Errorf("invalid migration file name %s", name)
+		}
+
+		versionPart, rest, found := strings.Cut(strings.TrimSuffix(name, "."+direction+".sql"), "_")
+		version, convErr := strconv.Atoi(versionPart)
+		if !found || convErr != nil {
+			return fmt.Errorf("invalid migration file name %s", name)
+		}
+
+		content, err := files.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: rest}
+			byVersion[version] = migration
+		}
+
+		if direction == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var migrations []Migration
+
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %04d_%s must have both up and down files", migration.Version, migration.Name)
+		}
+
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
@@ -0,0 +1,38 @@
+CREATE TABLE IF NOT EXISTS users (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    occupation VARCHAR(255) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    password VARCHAR(255) NOT NULL,
+    avatar_file_name VARCHAR(255) NULL,
+    role VARCHAR(255) NOT NULL,
+    totp_secret VARCHAR(255) NOT NULL DEFAULT '',
+    totp_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- database lama dibuat dari db_crowdfunding.sql tanpa kolom 2FA
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_secret VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_enabled BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- email di database lama bisa dobel dengan huruf besar/kecil berbeda, tanpa
+-- pengecekan ini index unik di bawah gagal tanpa menyebut baris mana
+DO $$
+DECLARE
+    duplicates TEXT;
+BEGIN
+    SELECT string_agg(email || ' (id ' || ids || ')', '; ') INTO duplicates
+    FROM (
+        SELECT LOWER(email) AS email, string_agg(id::TEXT, ', ' ORDER BY id) AS ids
+        FROM users
+        GROUP BY LOWER(email)
+        HAVING COUNT(*) > 1
+    ) AS duplicate_emails;
+
+    IF duplicates IS NOT NULL THEN
+        RAISE EXCEPTION 'users contains duplicate emails (case-insensitive), merge or rename them before migrating: %', duplicates;
+    END IF;
+END $$;
+
+CREATE UNIQUE INDEX IF NOT EXISTS users_email_key ON users (LOWER(email));
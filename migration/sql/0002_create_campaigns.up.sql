@@ -0,0 +1,51 @@
+CREATE TABLE IF NOT EXISTS campaigns (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    short_description VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL,
+    perks TEXT NOT NULL,
+    backer_count INT NOT NULL,
+    goal_amount INT NOT NULL,
+    current_amount INT NOT NULL,
+    slug VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- database lama bisa berisi campaign tanpa user atau slug yang dobel, laporkan
+-- barisnya dulu supaya foreign key / index unik tidak gagal tanpa penjelasan
+DO $$
+DECLARE
+    orphans TEXT;
+    duplicates TEXT;
+BEGIN
+    SELECT string_agg('id ' || c.id || ' (user_id ' || c.user_id || ')', '; ' ORDER BY c.id) INTO orphans
+    FROM campaigns c
+    LEFT JOIN users u ON u.id = c.user_id
+    WHERE u.id IS NULL;
+
+    IF orphans IS NOT NULL THEN
+        RAISE EXCEPTION 'campaigns references users that do not exist, delete or reassign them before migrating: %', orphans;
+    END IF;
+
+    SELECT string_agg(slug || ' (id ' || ids || ')', '; ') INTO duplicates
+    FROM (
+        SELECT slug, string_agg(id::TEXT, ', ' ORDER BY id) AS ids
+        FROM campaigns
+        GROUP BY slug
+        HAVING COUNT(*) > 1
+    ) AS duplicate_slugs;
+
+    IF duplicates IS NOT NULL THEN
+        RAISE EXCEPTION 'campaigns contains duplicate slugs, rename them before migrating: %', duplicates;
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'campaigns_user_id_fkey') THEN
+        ALTER TABLE campaigns
+            ADD CONSTRAINT campaigns_user_id_fkey FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;
+    END IF;
+END $$;
+
+CREATE UNIQUE INDEX IF NOT EXISTS campaigns_slug_key ON campaigns (slug);
+CREATE INDEX IF NOT EXISTS campaigns_user_id_idx ON campaigns (user_id);
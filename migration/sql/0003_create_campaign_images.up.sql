@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS campaign_images (
+    id SERIAL PRIMARY KEY,
+    campaign_id INT NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    is_primary SMALLINT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- sama seperti campaigns, gambar tanpa campaign dilaporkan sebelum foreign key dibuat
+DO $$
+DECLARE
+    orphans TEXT;
+BEGIN
+    SELECT string_agg('id ' || i.id || ' (campaign_id ' || i.campaign_id || ')', '; ' ORDER BY i.id) INTO orphans
+    FROM campaign_images i
+    LEFT JOIN campaigns c ON c.id = i.campaign_id
+    WHERE c.id IS NULL;
+
+    IF orphans IS NOT NULL THEN
+        RAISE EXCEPTION 'campaign_images references campaigns that do not exist, delete them before migrating: %', orphans;
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'campaign_images_campaign_id_fkey') THEN
+        ALTER TABLE campaign_images
+            ADD CONSTRAINT campaign_images_campaign_id_fkey FOREIGN KEY (campaign_id) REFERENCES campaigns (id) ON DELETE CASCADE;
+    END IF;
+END $$;
+
+CREATE INDEX IF NOT EXISTS campaign_images_campaign_id_idx ON campaign_images (campaign_id);
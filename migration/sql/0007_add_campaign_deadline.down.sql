@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS campaigns_open_deadline_idx;
+
+ALTER TABLE campaigns DROP COLUMN IF EXISTS closed_at;
+ALTER TABLE campaigns DROP COLUMN IF EXISTS deadline;
@@ -0,0 +1,4 @@
+ALTER TABLE campaigns ADD COLUMN deadline TIMESTAMP NULL;
+ALTER TABLE campaigns ADD COLUMN closed_at TIMESTAMP NULL;
+
+CREATE INDEX campaigns_open_deadline_idx ON campaigns (deadline) WHERE closed_at IS NULL;
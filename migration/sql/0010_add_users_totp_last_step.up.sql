@@ -0,0 +1,2 @@
+-- time step TOTP terakhir yang diterima, kode di step yang sama atau sebelumnya ditolak
+ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_last_step BIGINT NOT NULL DEFAULT 0;
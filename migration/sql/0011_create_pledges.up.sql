@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS pledges (
+    id SERIAL PRIMARY KEY,
+    campaign_id INT NOT NULL REFERENCES campaigns (id),
+    user_id INT NOT NULL REFERENCES users (id),
+    amount INT NOT NULL CHECK (amount > 0),
+    public BOOLEAN NOT NULL DEFAULT TRUE,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    paid_at TIMESTAMP NULL,
+    refunded_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS pledges_campaign_id_idx ON pledges (campaign_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS pledges_user_id_idx ON pledges (user_id);
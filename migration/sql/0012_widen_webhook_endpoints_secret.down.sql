@@ -0,0 +1,2 @@
+-- gagal kalau sudah ada secret terenkripsi yang lebih panjang dari 64 karakter
+ALTER TABLE webhook_endpoints ALTER COLUMN secret TYPE VARCHAR(64);
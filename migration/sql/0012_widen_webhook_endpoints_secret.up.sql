@@ -0,0 +1,3 @@
+-- secret dienkripsi dengan APP_KEY, ciphertext lebih panjang dari secret aslinya.
+-- Secret lama yang masih plain dienkripsi saat webhook berikutnya dikirim.
+ALTER TABLE webhook_endpoints ALTER COLUMN secret TYPE VARCHAR(255);
@@ -0,0 +1,95 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+)
+
+// githubProvider tidak mendukung OIDC, identity diambil dari REST API
+type githubProvider struct {
+	apiURL      string
+	oauthConfig oauth2.Config
+}
+
+func NewGitHubProvider(apiURL string, oauthConfig oauth2.Config) *githubProvider {
+	return &githubProvider{strings.TrimRight(apiURL, "/"), oauthConfig}
+}
+
+func (p *githubProvider) Name() string {
+	return "github"
+}
+
+func (p *githubProvider) Exchange(ctx context.Context, code string, codeVerifier string, redirectURI string) (Identity, error) {
+	identity := Identity{Provider: p.Name()}
+
+	token, err := exchange(ctx, p.oauthConfig, code, codeVerifier, redirectURI)
+	if err != nil {
+		return identity, err
+	}
+
+	client := p.oauthConfig.Client(ctx, token)
+
+	var profile struct {
+		ID    int64  `json:"id"`
+		Login string `json:"login"`
+		Name  string `json:"name"`
+	}
+
+	err = p.get(ctx, client, "/user", &profile)
+	if err != nil {
+		return identity, err
+	}
+
+	var emails []struct {
+		Email    string `json:"email"`
+		Primary  bool   `json:"primary"`
+		Verified bool   `json:"verified"`
+	}
+
+	err = p.get(ctx, client, "/user/emails", &emails)
+	if err != nil {
+		return identity, err
+	}
+
+	for _, email := range emails {
+		if email.Primary {
+			identity.Email = normalizeEmail(email.Email)
+			identity.EmailVerified = email.Verified
+		}
+	}
+
+	identity.Subject = strconv.FormatInt(profile.ID, 10)
+	identity.Name = profile.Name
+	if identity.Name == "" {
+		identity.Name = profile.Login
+	}
+
+	return identity, nil
+}
+
+func (p *githubProvider) get(ctx context.Context, client *http.Client, path string, target interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.apiURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.Wrap(ErrExchangeFailed, err.Error())
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.Wrap(ErrExchangeFailed, fmt.Sprintf("GET %s returned %d", path, resp.StatusCode))
+	}
+
+	return json.NewDecoder(resp.Body).Decode(target)
+}
@@ -0,0 +1,91 @@
+package oauth
+
+import (
+	"context"
+	"sync"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+)
+
+type oidcProvider struct {
+	name        string
+	issuer      string
+	oauthConfig oauth2.Config
+
+	mu       sync.Mutex
+	provider *oidc.Provider
+}
+
+func NewOIDCProvider(name string, issuer string, oauthConfig oauth2.Config) *oidcProvider {
+	return &oidcProvider{name: name, issuer: issuer, oauthConfig: oauthConfig}
+}
+
+func (p *oidcProvider) Name() string {
+	return p.name
+}
+
+func (p *oidcProvider) Exchange(ctx context.Context, code string, codeVerifier string, redirectURI string) (Identity, error) {
+	identity := Identity{Provider: p.name}
+
+	provider, err := p.discover(ctx)
+	if err != nil {
+		return identity, err
+	}
+
+	oauthConfig := p.oauthConfig
+	oauthConfig.Endpoint = provider.Endpoint()
+
+	token, err := exchange(ctx, oauthConfig, code, codeVerifier, redirectURI)
+	if err != nil {
+		return identity, err
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return identity, errors.Wrap(ErrExchangeFailed, "id_token is missing")
+	}
+
+	idToken, err := provider.Verifier(&oidc.Config{ClientID: p.oauthConfig.ClientID}).Verify(ctx, rawIDToken)
+	if err != nil {
+		return identity, errors.Wrap(ErrExchangeFailed, err.Error())
+	}
+
+	var claims struct {
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+		Name          string `json:"name"`
+	}
+
+	err = idToken.Claims(&claims)
+	if err != nil {
+		return identity, errors.Wrap(ErrExchangeFailed, err.Error())
+	}
+
+	identity.Subject = idToken.Subject
+	identity.Email = normalizeEmail(claims.Email)
+	identity.EmailVerified = claims.EmailVerified
+	identity.Name = claims.Name
+
+	return identity, nil
+}
+
+// discover dilakukan saat pertama kali dipakai supaya server tetap bisa jalan
+// walaupun issuer sedang tidak bisa diakses
+func (p *oidcProvider) discover(ctx context.Context) (*oidc.Provider, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.provider != nil {
+		return p.provider, nil
+	}
+
+	provider, err := oidc.NewProvider(ctx, p.issuer)
+	if err != nil {
+		return nil, errors.Wrap(ErrExchangeFailed, err.Error())
+	}
+
+	p.provider = provider
+	return provider, nil
+}
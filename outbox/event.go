@@ -0,0 +1,87 @@
+package outbox
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Event adalah domain event yang disimpan di tabel outbox_events pada
+// transaksi yang sama dengan perubahan datanya, lalu dikirim oleh Relay
+type Event struct {
+	ID            int64      `json:"id"`
+	Type          string     `json:"type"`
+	AggregateID   int        `json:"aggregate_id"`
+	Payload       JSON       `json:"payload" gorm:"type:jsonb"`
+	Attempts      int        `json:"-"`
+	LastError     string     `json:"-"`
+	NextAttemptAt time.Time  `json:"-"`
+	PublishedAt   *time.Time `json:"-"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// JSON disimpan di kolom jsonb dan di-encode apa adanya saat event dikirim
+type JSON json.RawMessage
+
+func (j JSON) Value() (driver.Value, error) {
+	return string(j), nil
+}
+
+func (j *JSON) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case []byte:
+		*j = append(JSON{}, value...)
+	case string:
+		*j = JSON(value)
+	default:
+		return fmt.Errorf("cannot scan %T into outbox.JSON", src)
+	}
+
+	return nil
+}
+
+func (j JSON) MarshalJSON() ([]byte, error) {
+	if len(j) == 0 {
+		return []byte("null"), nil
+	}
+
+	return j, nil
+}
+
+func (j *JSON) UnmarshalJSON(data []byte) error {
+	*j = append(JSON{}, data...)
+	return nil
+}
+
+func (Event) TableName() string {
+	return "outbox_events"
+}
+
+// Decode membaca payload ke struct milik domain, contoh campaign.CampaignEvent
+func (e Event) Decode(payload interface{}) error {
+	return json.Unmarshal(e.Payload, payload)
+}
+
+// Add menulis event memakai tx milik perubahan domain, event hanya tersimpan
+// kalau transaksi tersebut commit
+func Add(tx *gorm.DB, eventType string, aggregateID int, payload interface{}) error {
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	event := Event{
+		Type:          eventType,
+		AggregateID:   aggregateID,
+		Payload:       encoded,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}
+
+	return tx.Create(&event).Error
+}
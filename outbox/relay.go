@@ -0,0 +1,228 @@
+package outbox
+
+import (
+	"auth-gorm-echo/jobs"
+	"auth-gorm-echo/metrics"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Subscriber dipanggil untuk setiap event dengan tipe yang di-subscribe.
+// Delivery at-least-once: event yang sama bisa diterima lebih dari sekali.
+type Subscriber func(ctx context.Context, event Event) error
+
+type RelayConfig struct {
+	BatchSize    int
+	PollInterval time.Duration
+	// jeda sebelum event yang gagal dikirim dicoba lagi: BaseBackoff * 2^(attempt-1)
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	// event yang sudah terkirim dihapus setelah Retention
+	Retention time.Duration
+	// lama satu batch dipegang relay, kalau relay mati event dikirim ulang setelahnya
+	ClaimTimeout time.Duration
+}
+
+// Relay membaca event yang belum terkirim dari outbox_events lalu
+// mengirimkannya ke subscriber in-process dan ke antrian job. Beberapa relay
+// boleh berjalan bersamaan karena baris diklaim dengan SKIP LOCKED.
+type Relay struct {
+	db          *gorm.DB
+	queue       *jobs.Queue
+	config      RelayConfig
+	logger      *slog.Logger
+	subscribers map[string][]Subscriber
+	forwarded   map[string]bool
+}
+
+func NewRelay(db *gorm.DB, queue *jobs.Queue, config RelayConfig, logger *slog.Logger) *Relay {
+	return &Relay{
+		db:          db,
+		queue:       queue,
+		config:      config,
+		logger:      logger,
+		subscribers: map[string][]Subscriber{},
+		forwarded:   map[string]bool{},
+	}
+}
+
+// JobType adalah tipe job untuk event yang diteruskan ke antrian, contoh
+// "event.campaign.closed"
+func JobType(eventType string) jobs.Type[Event] {
+	return jobs.NewType[Event]("event." + eventType)
+}
+
+func (r *Relay) Subscribe(eventType string, subscriber Subscriber) {
+	r.subscribers[eventType] = append(r.subscribers[eventType], subscriber)
+}
+
+// Forward meneruskan event ke antrian job sebagai JobType(eventType), worker
+// harus punya handler untuk tipe job tersebut
+func (r *Relay) Forward(eventType string) {
+	r.forwarded[eventType] = true
+}
+
+// Run mengirim event sampai ctx dibatalkan
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.config.PollInterval)
+	defer ticker.Stop()
+
+	lastCleanup := time.Time{}
+
+	for {
+		// kosongkan outbox dulu sebelum menunggu tick berikutnya
+		for ctx.Err() == nil {
+			count, err := r.publishBatch(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					r.logger.ErrorContext(ctx, "failed to publish outbox events", "error", err)
+				}
+				break
+			}
+
+			if count < r.config.BatchSize {
+				break
+			}
+		}
+
+		if time.Since(lastCleanup) > time.Hour {
+			r.cleanup(ctx)
+			lastCleanup = time.Now()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Relay) publishBatch(ctx context.Context) (int, error) {
+	events, err := r.claim(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, event := range events {
+		publishErr := r.publish(ctx, event)
+		if publishErr != nil {
+			event.Attempts++
+			retryIn := r.backoff(event.Attempts)
+
+			metrics.OutboxEvents.WithLabelValues(event.Type, "retry").Inc()
+			r.logger.WarnContext(ctx, "failed to publish outbox event", "event_id", event.ID, "event_type", event.Type, "attempt", event.Attempts, "retry_in", retryIn.String(), "error", publishErr)
+
+			err = r.db.WithContext(ctx).Model(&Event{}).Where("id = ?", event.ID).Updates(map[string]interface{}{
+				"attempts":        event.Attempts,
+				"last_error":      publishErr.Error(),
+				"next_attempt_at": time.Now().Add(retryIn),
+			}).Error
+			if err != nil {
+				return len(events), err
+			}
+
+			continue
+		}
+
+		err = r.db.WithContext(ctx).Model(&Event{}).Where("id = ?", event.ID).Update("published_at", time.Now()).Error
+		if err != nil {
+			return len(events), err
+		}
+
+		metrics.OutboxEvents.WithLabelValues(event.Type, "published").Inc()
+	}
+
+	return len(events), nil
+}
+
+// claim mengambil satu batch event dan memundurkan next_attempt_at-nya
+// sebanyak ClaimTimeout, lalu transaksi langsung selesai. Subscriber dijalankan
+// di luar transaksi supaya lock tidak ditahan selama event dikirim.
+func (r *Relay) claim(ctx context.Context) ([]Event, error) {
+	var events []Event
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("published_at IS NULL AND next_attempt_at <= ?", time.Now()).
+			Order("id").
+			Limit(r.config.BatchSize).
+			Find(&events).Error
+		if err != nil || len(events) == 0 {
+			return err
+		}
+
+		ids := make([]int64, len(events))
+		for i, event := range events {
+			ids[i] = event.ID
+		}
+
+		return tx.Model(&Event{}).Where("id IN ?", ids).Update("next_attempt_at", time.Now().Add(r.config.ClaimTimeout)).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func (r *Relay) publish(ctx context.Context, event Event) error {
+	for _, subscriber := range r.subscribers[event.Type] {
+		err := callSubscriber(ctx, subscriber, event)
+		if err != nil {
+			return err
+		}
+	}
+
+	if r.forwarded[event.Type] {
+		// id tetap supaya event yang dikirim ulang tidak membuat job ganda selama job lama masih antri
+		_, err := JobType(event.Type).Enqueue(ctx, r.queue, event, jobs.ID(fmt.Sprintf("outbox-%d", event.ID)))
+		if err != nil && !errors.Is(err, jobs.ErrDuplicateJob) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func callSubscriber(ctx context.Context, subscriber Subscriber, event Event) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("panic: %v", recovered)
+		}
+	}()
+
+	return subscriber(ctx, event)
+}
+
+func (r *Relay) cleanup(ctx context.Context) {
+	result := r.db.WithContext(ctx).
+		Where("published_at IS NOT NULL AND published_at < ?", time.Now().Add(-r.config.Retention)).
+		Delete(&Event{})
+	if result.Error != nil {
+		if ctx.Err() == nil {
+			r.logger.ErrorContext(ctx, "failed to delete published outbox events", "error", result.Error)
+		}
+		return
+	}
+
+	if result.RowsAffected > 0 {
+		r.logger.InfoContext(ctx, "deleted published outbox events", "count", result.RowsAffected)
+	}
+}
+
+func (r *Relay) backoff(attempt int) time.Duration {
+	delay := float64(r.config.BaseBackoff) * math.Pow(2, float64(attempt-1))
+	if delay > float64(r.config.MaxBackoff) {
+		delay = float64(r.config.MaxBackoff)
+	}
+
+	return time.Duration(delay)
+}
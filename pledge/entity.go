@@ -0,0 +1,69 @@
+package pledge
+
+import (
+	"auth-gorm-echo/campaign"
+	"auth-gorm-echo/user"
+	"time"
+)
+
+// status pledge: pending -> paid -> refunded
+const (
+	StatusPending  = "pending"
+	StatusPaid     = "paid"
+	StatusRefunded = "refunded"
+)
+
+// tipe domain event pledge di outbox, namanya sama dengan event webhook
+const (
+	EventPledgePaid     = "pledge.paid"
+	EventPledgeRefunded = "pledge.refunded"
+)
+
+type Pledge struct {
+	ID         int
+	CampaignID int
+	UserID     int
+	Amount     int
+	// nama backer boleh ditampilkan di halaman campaign
+	Public     bool
+	Status     string
+	PaidAt     *time.Time
+	RefundedAt *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	User       user.User
+}
+
+// BackerName adalah nama yang boleh ditampilkan publik, kosong kalau backer
+// tidak mau namanya ditampilkan. User harus sudah di-load.
+func (p Pledge) BackerName() string {
+	if !p.Public {
+		return ""
+	}
+
+	return p.User.Name
+}
+
+// PledgeEvent adalah payload pledge.paid dan pledge.refunded
+type PledgeEvent struct {
+	ID         int        `json:"id"`
+	Amount     int        `json:"amount"`
+	Status     string     `json:"status"`
+	BackerName string     `json:"backer_name"`
+	PaidAt     *time.Time `json:"paid_at"`
+	RefundedAt *time.Time `json:"refunded_at"`
+	// campaign setelah pledge dihitung, UserID adalah pemilik campaign
+	Campaign campaign.CampaignEvent `json:"campaign"`
+}
+
+func NewPledgeEvent(pledge Pledge, pledgeCampaign campaign.Campaign) PledgeEvent {
+	return PledgeEvent{
+		ID:         pledge.ID,
+		Amount:     pledge.Amount,
+		Status:     pledge.Status,
+		BackerName: pledge.BackerName(),
+		PaidAt:     pledge.PaidAt,
+		RefundedAt: pledge.RefundedAt,
+		Campaign:   campaign.NewCampaignEvent(pledgeCampaign),
+	}
+}
@@ -0,0 +1,41 @@
+package pledge
+
+import "time"
+
+type PledgeFormatter struct {
+	ID         int        `json:"id"`
+	CampaignID int        `json:"campaign_id"`
+	Amount     int        `json:"amount"`
+	Public     bool       `json:"public"`
+	BackerName string     `json:"backer_name"`
+	Status     string     `json:"status"`
+	PaidAt     *time.Time `json:"paid_at"`
+	RefundedAt *time.Time `json:"refunded_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+func FormatPledge(pledge Pledge) PledgeFormatter {
+	formatter := PledgeFormatter{
+		ID:         pledge.ID,
+		CampaignID: pledge.CampaignID,
+		Amount:     pledge.Amount,
+		Public:     pledge.Public,
+		BackerName: pledge.BackerName(),
+		Status:     pledge.Status,
+		PaidAt:     pledge.PaidAt,
+		RefundedAt: pledge.RefundedAt,
+		CreatedAt:  pledge.CreatedAt,
+	}
+
+	return formatter
+}
+
+func FormatPledges(pledges []Pledge) []PledgeFormatter {
+	pledgesFormatter := []PledgeFormatter{}
+
+	for _, pledge := range pledges {
+		pledgesFormatter = append(pledgesFormatter, FormatPledge(pledge))
+	}
+
+	return pledgesFormatter
+}
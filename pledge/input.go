@@ -0,0 +1,21 @@
+package pledge
+
+import "auth-gorm-echo/user"
+
+type CreatePledgeInput struct {
+	CampaignID int  `param:"id" validate:"required"`
+	Amount     int  `json:"amount" validate:"required,gt=0"`
+	Public     bool `json:"public"`
+	User       user.User
+}
+
+// CampaignPledgesInput dipakai route /campaigns/:id/pledges
+type CampaignPledgesInput struct {
+	CampaignID int `param:"id" validate:"required"`
+	User       user.User
+}
+
+// PledgeInput dipakai route admin /admin/pledges/:id
+type PledgeInput struct {
+	ID int `param:"id" validate:"required"`
+}
@@ -0,0 +1,151 @@
+package pledge
+
+import (
+	"auth-gorm-echo/campaign"
+	"auth-gorm-echo/outbox"
+	"auth-gorm-echo/tracing"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Repository interface {
+	Save(ctx context.Context, pledge Pledge) (Pledge, error)
+	FindByCampaignID(ctx context.Context, campaignID int) ([]Pledge, error)
+	MarkPaid(ctx context.Context, ID int, paidAt time.Time) (Pledge, campaign.Campaign, error)
+	MarkRefunded(ctx context.Context, ID int, refundedAt time.Time) (Pledge, campaign.Campaign, error)
+}
+
+type repository struct {
+	db      *gorm.DB
+	timeout time.Duration
+}
+
+func NewRepository(db *gorm.DB, timeout time.Duration) *repository {
+	return &repository{db, timeout}
+}
+
+func (r *repository) withTimeout(ctx context.Context) (*gorm.DB, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	return r.db.WithContext(ctx), cancel
+}
+
+func (r *repository) Save(ctx context.Context, pledge Pledge) (Pledge, error) {
+	ctx, span := tracing.Start(ctx, "pledge.Repository.Save")
+	defer span.End()
+
+	db, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	// user hanya dibaca, jangan ikut disimpan
+	err := db.Omit(clause.Associations).Create(&pledge).Error
+	if err != nil {
+		tracing.RecordError(span, err)
+		return pledge, err
+	}
+
+	return pledge, nil
+}
+
+func (r *repository) FindByCampaignID(ctx context.Context, campaignID int) ([]Pledge, error) {
+	ctx, span := tracing.Start(ctx, "pledge.Repository.FindByCampaignID")
+	defer span.End()
+
+	db, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	var pledges []Pledge
+
+	err := db.Where("campaign_id = ?", campaignID).Preload("User").Order("created_at desc").Find(&pledges).Error
+	if err != nil {
+		tracing.RecordError(span, err)
+		return pledges, err
+	}
+
+	return pledges, nil
+}
+
+// MarkPaid mengubah pledge pending menjadi paid dan menambah dana campaign
+// dalam satu transaksi bersama event pledge.paid / campaign.backed di outbox
+func (r *repository) MarkPaid(ctx context.Context, ID int, paidAt time.Time) (Pledge, campaign.Campaign, error) {
+	ctx, span := tracing.Start(ctx, "pledge.Repository.MarkPaid")
+	defer span.End()
+
+	pledge, pledgeCampaign, err := r.transition(ctx, ID, StatusPending, StatusPaid, map[string]interface{}{"paid_at": paidAt}, EventPledgePaid,
+		func(tx *gorm.DB, pledge Pledge) (campaign.Campaign, error) {
+			return campaign.AddBacking(tx, pledge.CampaignID, pledge.Amount, pledge.BackerName())
+		})
+	if err != nil && !errors.Is(err, ErrPledgeNotFound) && !errors.Is(err, ErrInvalidStatus) {
+		tracing.RecordError(span, err)
+	}
+
+	return pledge, pledgeCampaign, err
+}
+
+// MarkRefunded kebalikan MarkPaid, hanya pledge yang sudah paid yang bisa di-refund
+func (r *repository) MarkRefunded(ctx context.Context, ID int, refundedAt time.Time) (Pledge, campaign.Campaign, error) {
+	ctx, span := tracing.Start(ctx, "pledge.Repository.MarkRefunded")
+	defer span.End()
+
+	pledge, pledgeCampaign, err := r.transition(ctx, ID, StatusPaid, StatusRefunded, map[string]interface{}{"refunded_at": refundedAt}, EventPledgeRefunded,
+		func(tx *gorm.DB, pledge Pledge) (campaign.Campaign, error) {
+			return campaign.RemoveBacking(tx, pledge.CampaignID, pledge.Amount)
+		})
+	if err != nil && !errors.Is(err, ErrPledgeNotFound) && !errors.Is(err, ErrInvalidStatus) {
+		tracing.RecordError(span, err)
+	}
+
+	return pledge, pledgeCampaign, err
+}
+
+// transition mengubah status pledge dari from ke to. Kondisi status ada di
+// WHERE sehingga pledge yang sama tidak bisa dihitung dua kali walaupun
+// dua request settle berjalan bersamaan.
+func (r *repository) transition(ctx context.Context, ID int, from string, to string, updates map[string]interface{}, eventType string, apply func(tx *gorm.DB, pledge Pledge) (campaign.Campaign, error)) (Pledge, campaign.Campaign, error) {
+	db, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	var pledge Pledge
+	var pledgeCampaign campaign.Campaign
+
+	updates["status"] = to
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&pledge).
+			Clauses(clause.Returning{}).
+			Where("id = ? AND status = ?", ID, from).
+			Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			err := tx.Select("id").Where("id = ?", ID).Take(&Pledge{}).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrPledgeNotFound
+			}
+			if err != nil {
+				return err
+			}
+
+			return ErrInvalidStatus
+		}
+
+		// nama backer untuk event, hanya dipakai kalau pledge public
+		err := tx.Select("id", "name").Where("id = ?", pledge.UserID).Take(&pledge.User).Error
+		if err != nil {
+			return err
+		}
+
+		pledgeCampaign, err = apply(tx, pledge)
+		if err != nil {
+			return err
+		}
+
+		return outbox.Add(tx, eventType, pledge.ID, NewPledgeEvent(pledge, pledgeCampaign))
+	})
+
+	return pledge, pledgeCampaign, err
+}
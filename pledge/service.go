@@ -0,0 +1,127 @@
+package pledge
+
+import (
+	"auth-gorm-echo/apperror"
+	"auth-gorm-echo/campaign"
+	"auth-gorm-echo/metrics"
+	"auth-gorm-echo/tracing"
+	"context"
+	"log/slog"
+	"time"
+)
+
+var (
+	ErrPledgeNotFound = apperror.NotFound("pledge.not_found", "Pledge not found")
+	ErrCampaignClosed = apperror.Conflict("pledge.campaign_closed", "Campaign is no longer accepting pledges")
+	ErrInvalidStatus  = apperror.Conflict("pledge.invalid_status", "Pledge cannot move to that status")
+)
+
+type Service interface {
+	CreatePledge(ctx context.Context, input CreatePledgeInput) (Pledge, error)
+	GetCampaignPledges(ctx context.Context, input CampaignPledgesInput) ([]Pledge, error)
+	MarkPaid(ctx context.Context, input PledgeInput) (Pledge, error)
+	Refund(ctx context.Context, input PledgeInput) (Pledge, error)
+}
+
+// CampaignCache dipenuhi campaign.CachedRepository. Dana campaign diubah di
+// transaksi pledge, bukan lewat campaign.Repository, jadi cache-nya harus
+// dihapus di sini setelah transaksi di-commit.
+type CampaignCache interface {
+	Invalidate(ctx context.Context, campaignID int)
+}
+
+type service struct {
+	repository Repository
+	campaigns  campaign.Repository
+	cache      CampaignCache
+	logger     *slog.Logger
+}
+
+func NewService(repository Repository, campaigns campaign.Repository, cache CampaignCache, logger *slog.Logger) *service {
+	return &service{repository, campaigns, cache, logger}
+}
+
+// CreatePledge mencatat pledge yang belum dibayar, dana campaign baru
+// bertambah saat pledge settle lewat MarkPaid
+func (s *service) CreatePledge(ctx context.Context, input CreatePledgeInput) (Pledge, error) {
+	ctx, span := tracing.Start(ctx, "pledge.Service.CreatePledge")
+	defer span.End()
+
+	pledgeCampaign, err := s.campaigns.FindByID(ctx, input.CampaignID)
+	if err != nil {
+		return Pledge{}, err
+	}
+
+	if pledgeCampaign.ClosedAt != nil || (pledgeCampaign.Deadline != nil && pledgeCampaign.Deadline.Before(time.Now())) {
+		return Pledge{}, ErrCampaignClosed
+	}
+
+	pledge := Pledge{
+		CampaignID: input.CampaignID,
+		UserID:     input.User.ID,
+		Amount:     input.Amount,
+		Public:     input.Public,
+		Status:     StatusPending,
+	}
+
+	newPledge, err := s.repository.Save(ctx, pledge)
+	if err != nil {
+		return newPledge, err
+	}
+
+	newPledge.User = input.User
+
+	return newPledge, nil
+}
+
+// GetCampaignPledges hanya untuk pemilik campaign
+func (s *service) GetCampaignPledges(ctx context.Context, input CampaignPledgesInput) ([]Pledge, error) {
+	ctx, span := tracing.Start(ctx, "pledge.Service.GetCampaignPledges")
+	defer span.End()
+
+	pledgeCampaign, err := s.campaigns.FindByID(ctx, input.CampaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	if pledgeCampaign.UserID != input.User.ID {
+		return nil, campaign.ErrNotCampaignOwner
+	}
+
+	return s.repository.FindByCampaignID(ctx, input.CampaignID)
+}
+
+// MarkPaid dipanggil saat pembayaran pledge settle. Dana campaign, event
+// pledge.paid / campaign.backed / campaign.funded ditulis dalam satu transaksi.
+func (s *service) MarkPaid(ctx context.Context, input PledgeInput) (Pledge, error) {
+	ctx, span := tracing.Start(ctx, "pledge.Service.MarkPaid")
+	defer span.End()
+
+	pledge, pledgeCampaign, err := s.repository.MarkPaid(ctx, input.ID, time.Now())
+	if err != nil {
+		return pledge, err
+	}
+
+	s.cache.Invalidate(ctx, pledge.CampaignID)
+
+	metrics.RecordPledgePaid(pledge.Amount)
+	s.logger.InfoContext(ctx, "pledge paid", "pledge_id", pledge.ID, "campaign_id", pledge.CampaignID, "amount", pledge.Amount, "current_amount", pledgeCampaign.CurrentAmount)
+
+	return pledge, nil
+}
+
+func (s *service) Refund(ctx context.Context, input PledgeInput) (Pledge, error) {
+	ctx, span := tracing.Start(ctx, "pledge.Service.Refund")
+	defer span.End()
+
+	pledge, pledgeCampaign, err := s.repository.MarkRefunded(ctx, input.ID, time.Now())
+	if err != nil {
+		return pledge, err
+	}
+
+	s.cache.Invalidate(ctx, pledge.CampaignID)
+
+	s.logger.InfoContext(ctx, "pledge refunded", "pledge_id", pledge.ID, "campaign_id", pledge.CampaignID, "amount", pledge.Amount, "current_amount", pledgeCampaign.CurrentAmount)
+
+	return pledge, nil
+}
@@ -0,0 +1,99 @@
+package ratelimit
+
+import (
+	"auth-gorm-echo/apperror"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var ErrRateLimited = apperror.TooManyRequests("rate_limit.exceeded", "Too many requests, try again later")
+
+// slidingWindow menyimpan waktu setiap request di sorted set, request yang
+// sudah keluar dari window dibuang lebih dulu. Waktu diambil dari redis supaya
+// semua instance server memakai jam yang sama.
+// KEYS[1] key, ARGV[1] window (ms), ARGV[2] limit, ARGV[3] member unik
+var slidingWindow = redis.NewScript(`
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+local window = tonumber(ARGV[1])
+local limit = tonumber(ARGV[2])
+
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+
+local count = redis.call('ZCARD', KEYS[1])
+local allowed = 0
+if count < limit then
+	redis.call('ZADD', KEYS[1], now, ARGV[3])
+	count = count + 1
+	allowed = 1
+end
+
+redis.call('PEXPIRE', KEYS[1], window)
+
+local reset = window
+local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+if oldest[2] then
+	reset = tonumber(oldest[2]) + window - now
+end
+
+return {allowed, count, reset}
+`)
+
+// Result adalah hasil pengecekan satu request
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// ResetAfter adalah sisa waktu sampai slot paling lama di window kosong lagi
+	ResetAfter time.Duration
+}
+
+type Limiter struct {
+	rdb    *redis.Client
+	logger *slog.Logger
+}
+
+func NewLimiter(rdb *redis.Client, logger *slog.Logger) *Limiter {
+	return &Limiter{rdb, logger}
+}
+
+// Allow mencatat satu request untuk key dan mengecek apakah masih di bawah limit
+func (l *Limiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (Result, error) {
+	result := Result{Limit: limit}
+
+	member, err := randomMember()
+	if err != nil {
+		return result, err
+	}
+
+	values, err := slidingWindow.Run(ctx, l.rdb, []string{key}, window.Milliseconds(), limit, member).Int64Slice()
+	if err != nil {
+		return result, err
+	}
+
+	result.Allowed = values[0] == 1
+	result.Remaining = limit - int(values[1])
+	result.ResetAfter = time.Duration(values[2]) * time.Millisecond
+
+	if result.Remaining < 0 {
+		result.Remaining = 0
+	}
+
+	return result, nil
+}
+
+// dua request di milidetik yang sama tetap harus tercatat dua kali
+func randomMember() (string, error) {
+	random := make([]byte, 8)
+	_, err := rand.Read(random)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(random), nil
+}
@@ -0,0 +1,56 @@
+package ratelimit
+
+import (
+	"auth-gorm-echo/logging"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestLimiterSlidingWindow(t *testing.T) {
+	server := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	ctx := context.Background()
+	limiter := NewLimiter(rdb, logging.New())
+
+	now := time.Unix(1700000000, 0)
+	server.SetTime(now)
+
+	tests := []struct {
+		name          string
+		advance       time.Duration
+		wantAllowed   bool
+		wantRemaining int
+	}{
+		{"request pertama", 0, true, 2},
+		{"request kedua", time.Second, true, 1},
+		{"request ketiga", time.Second, true, 0},
+		{"melebihi limit", time.Second, false, 0},
+		// request pertama keluar dari window, slot-nya bisa dipakai lagi
+		{"setelah slot tertua lewat", time.Millisecond * 7500, true, 0},
+		{"masih penuh", 0, false, 0},
+	}
+
+	for _, tt := range tests {
+		now = now.Add(tt.advance)
+		server.SetTime(now)
+
+		result, err := limiter.Allow(ctx, "test", 3, time.Second*10)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if result.Allowed != tt.wantAllowed || result.Remaining != tt.wantRemaining {
+			t.Errorf("%s: Allow() = allowed %v remaining %d, want allowed %v remaining %d",
+				tt.name, result.Allowed, result.Remaining, tt.wantAllowed, tt.wantRemaining)
+		}
+		if result.ResetAfter <= 0 || result.ResetAfter > time.Second*10 {
+			t.Errorf("%s: ResetAfter = %s, want within the window", tt.name, result.ResetAfter)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package seed
+
+import (
+	"auth-gorm-echo/campaign"
+	"auth-gorm-echo/upload"
+	"auth-gorm-echo/user"
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// DemoPassword adalah password semua user demo
+const DemoPassword = "password123"
+
+type demoUser struct {
+	Name       string
+	Occupation string
+	Email      string
+	Color      color.RGBA
+}
+
+type demoCampaign struct {
+	OwnerEmail       string
+	Name             string
+	ShortDescription string
+	Description      string
+	Perks            string
+	GoalAmount       int
+	CurrentAmount    int
+	BackerCount      int
+	Colors           []color.RGBA
+}
+
+var demoUsers = []demoUser{
+	{"Andi Pratama", "Petani Kopi", "andi@example.com", color.RGBA{121, 85, 72, 255}},
+	{"Dewi Lestari", "Guru SD", "dewi@example.com", color.RGBA{233, 30, 99, 255}},
+	{"Budi Santoso", "Software Engineer", "budi@example.com", color.RGBA{33, 150, 243, 255}},
+	{"Siti Rahmawati", "Desainer Grafis", "siti@example.com", color.RGBA{255, 152, 0, 255}},
+}
+
+var demoCampaigns = []demoCampaign{
+	{
+		OwnerEmail:       "andi@example.com",
+		Name:             "Kopi Gayo Untuk Semua",
+		ShortDescription: "Bantu petani kopi Gayo membangun rumah sangrai sendiri",
+		Description:      "Selama ini petani kopi di dataran tinggi Gayo menjual biji mentah dengan harga murah. Dana kampanye ini dipakai untuk membangun rumah sangrai bersama sehingga petani bisa menjual kopi siap seduh dengan harga yang lebih adil.",
+		Perks:            "Kopi Gayo 250gr, Tumbler eksklusif, Kunjungan ke kebun kopi",
+		GoalAmount:       50000000,
+		CurrentAmount:    12500000,
+		BackerCount:      48,
+		Colors:           []color.RGBA{{121, 85, 72, 255}, {215, 204, 200, 255}},
+	},
+	{
+		OwnerEmail:       "dewi@example.com",
+		Name:             "Perpustakaan Keliling Desa",
+		ShortDescription: "Motor perpustakaan keliling untuk anak-anak di pelosok desa",
+		Description:      "Banyak anak di desa kami belum pernah memegang buku cerita. Kami ingin membeli satu motor roda tiga beserta 1.000 buku bacaan anak yang akan berkeliling ke lima desa setiap minggu.",
+		Perks:            "Kartu ucapan dari anak-anak, Nama di rak buku, Kaos relawan",
+		GoalAmount:       30000000,
+		CurrentAmount:    27000000,
+		BackerCount:      120,
+		Colors:           []color.RGBA{{233, 30, 99, 255}, {248, 187, 208, 255}},
+	},
+	{
+		OwnerEmail:       "budi@example.com",
+		Name:             "Aplikasi Belajar Aksara Jawa",
+		ShortDescription: "Aplikasi gratis untuk belajar menulis aksara Jawa",
+		Description:      "Aksara Jawa semakin jarang dipelajari. Kami membuat aplikasi mobile gratis dengan latihan menulis interaktif, kuis dan cerita rakyat supaya generasi muda tetap mengenal aksara daerahnya.",
+		Perks:            "Akses beta, Nama di halaman kredit, Stiker aksara Jawa",
+		GoalAmount:       80000000,
+		CurrentAmount:    5000000,
+		BackerCount:      12,
+		Colors:           []color.RGBA{{33, 150, 243, 255}, {187, 222, 251, 255}},
+	},
+	{
+		OwnerEmail:       "siti@example.com",
+		Name:             "Batik Motif Nusantara",
+		ShortDescription: "Koleksi batik tulis dengan motif dari 34 provinsi",
+		Description:      "Bersama pengrajin batik di Pekalongan kami merancang koleksi batik tulis dengan motif khas dari setiap provinsi. Hasil penjualan dibagi langsung kepada pengrajin.",
+		Perks:            "Selendang batik, Kain batik tulis 2m, Workshop membatik",
+		GoalAmount:       45000000,
+		CurrentAmount:    45000000,
+		BackerCount:      210,
+		Colors:           []color.RGBA{{255, 152, 0, 255}, {255, 224, 178, 255}, {93, 64, 55, 255}},
+	},
+}
+
+type Seeder struct {
+	db                         *gorm.DB
+	userService                user.Service
+	campaignService            campaign.Service
+	avatarUploadService        upload.Service
+	campaignImageUploadService upload.Service
+}
+
+func NewSeeder(db *gorm.DB, userService user.Service, campaignService campaign.Service, avatarUploadService upload.Service, campaignImageUploadService upload.Service) *Seeder {
+	return &Seeder{db, userService, campaignService, avatarUploadService, campaignImageUploadService}
+}
+
+// Run mengisi database dengan data demo, user yang emailnya sudah ada dilewati
+// sehingga seed aman dijalankan berkali-kali
+func (s *Seeder) Run(ctx context.Context) error {
+	users := map[string]user.User{}
+
+	for _, demo := range demoUsers {
+		isAvailable, err := s.userService.IsEmailAvailable(ctx, user.CheckEmailInput{Email: demo.Email})
+		if err != nil {
+			return err
+		}
+
+		if !isAvailable {
+			log.Printf("seed: user %s already exists, skipping", demo.Email)
+			continue
+		}
+
+		newUser, err := s.userService.RegisterUser(ctx, user.RegisterUserInput{
+			Name:       demo.Name,
+			Occupation: demo.Occupation,
+			Email:      demo.Email,
+			Password:   DemoPassword,
+		})
+		if err != nil {
+			return err
+		}
+
+		avatar, err := s.avatarUploadService.SaveImage(ctx, placeholderImage(256, 256, demo.Color))
+		if err != nil {
+			return err
+		}
+
+		newUser, err = s.userService.SaveAvatar(ctx, newUser.ID, avatar.Path)
+		if err != nil {
+			return err
+		}
+
+		users[demo.Email] = newUser
+		log.Printf("seed: created user %s", demo.Email)
+	}
+
+	for _, demo := range demoCampaigns {
+		owner, ok := users[demo.OwnerEmail]
+		if !ok {
+			continue
+		}
+
+		newCampaign, err := s.campaignService.CreateCampaign(ctx, campaign.CreateCampaignInput{
+			Name:             demo.Name,
+			ShortDescription: demo.ShortDescription,
+			Description:      demo.Description,
+			GoalAmount:       demo.GoalAmount,
+			Perks:            demo.Perks,
+			User:             owner,
+		})
+		if err != nil {
+			return err
+		}
+
+		// jumlah dana dan backer tidak bisa diisi lewat service
+		err = s.db.Model(&campaign.Campaign{}).Where("id = ?", newCampaign.ID).Updates(map[string]interface{}{
+			"current_amount": demo.CurrentAmount,
+			"backer_count":   demo.BackerCount,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		for i, imageColor := range demo.Colors {
+			image, err := s.campaignImageUploadService.SaveImage(ctx, placeholderImage(800, 600, imageColor))
+			if err != nil {
+				return err
+			}
+
+			_, err = s.campaignService.SaveCampaignImage(ctx, campaign.CreateCampaignImageInput{
+				CampaignID: newCampaign.ID,
+				IsPrimary:  i == 0,
+				User:       owner,
+			}, image.Path)
+			if err != nil {
+				return err
+			}
+		}
+
+		log.Printf("seed: created campaign %q", demo.Name)
+	}
+
+	return nil
+}
+
+// placeholderImage membuat gambar gradasi sederhana sebagai pengganti foto
+func placeholderImage(width int, height int, base color.RGBA) *bytes.Buffer {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		shade := uint8(255 * y / height / 3)
+
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{
+				R: darken(base.R, shade),
+				G: darken(base.G, shade),
+				B: darken(base.B, shade),
+				A: 255,
+			})
+		}
+	}
+
+	var buffer bytes.Buffer
+	png.Encode(&buffer, img)
+
+	return &buffer
+}
+
+func darken(value uint8, amount uint8) uint8 {
+	if value < amount {
+		return 0
+	}
+
+	return value - amount
+}
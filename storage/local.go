@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type localStore struct {
+	dir     string
+	baseURL string
+}
+
+// NewLocalStore menyimpan file di disk, file dilayani oleh router.Static
+func NewLocalStore(dir string, baseURL string) *localStore {
+	return &localStore{dir, strings.TrimRight(baseURL, "/")}
+}
+
+func (s *localStore) Dir() string {
+	return s.dir
+}
+
+func (s *localStore) Put(ctx context.Context, key string, src io.Reader, size int64, contentType string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+
+	dst, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(dst, src)
+	if err != nil {
+		dst.Close()
+		os.Remove(path)
+		return err
+	}
+
+	return dst.Close()
+}
+
+func (s *localStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+
+	return file, err
+}
+
+func (s *localStore) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+func (s *localStore) URL(key string) string {
+	key, err := cleanKey(key)
+	if err != nil {
+		return ""
+	}
+
+	return s.baseURL + "/" + key
+}
+
+// SignedURL: file lokal selalu publik, jadi cukup kembalikan URL biasa
+func (s *localStore) SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	if _, err := cleanKey(key); err != nil {
+		return "", err
+	}
+
+	return s.URL(key), nil
+}
+
+func (s *localStore) path(key string) (string, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
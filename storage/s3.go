@@ -0,0 +1,122 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+type S3Config struct {
+	Endpoint  string
+	AccessKey string
+	SecretKey string
+	Bucket    string
+	Region    string
+	UseSSL    bool
+	// PublicURL opsional, contoh URL CDN di depan bucket
+	PublicURL string
+}
+
+// s3Store bisa dipakai dengan AWS S3 maupun server S3-compatible seperti MinIO
+type s3Store struct {
+	client    *minio.Client
+	bucket    string
+	publicURL string
+}
+
+func NewS3Store(config S3Config) (*s3Store, error) {
+	client, err := minio.New(config.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(config.AccessKey, config.SecretKey, ""),
+		Secure: config.UseSSL,
+		Region: config.Region,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	publicURL := config.PublicURL
+	if publicURL == "" {
+		scheme := "http"
+		if config.UseSSL {
+			scheme = "https"
+		}
+
+		publicURL = fmt.Sprintf("%s://%s/%s", scheme, config.Endpoint, config.Bucket)
+	}
+
+	return &s3Store{client, config.Bucket, strings.TrimRight(publicURL, "/")}, nil
+}
+
+func (s *s3Store) Put(ctx context.Context, key string, src io.Reader, size int64, contentType string) error {
+	key, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.PutObject(ctx, s.bucket, key, src, size, minio.PutObjectOptions{ContentType: contentType, CacheControl: ImmutableCacheControl})
+	return err
+}
+
+func (s *s3Store) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	object, err := s.client.GetObject(ctx, s.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	// GetObject baru request ke server saat dibaca, Stat untuk cek file ada
+	_, err = object.Stat()
+	if err != nil {
+		object.Close()
+
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, ErrNotFound
+		}
+
+		return nil, err
+	}
+
+	return object, nil
+}
+
+func (s *s3Store) Delete(ctx context.Context, key string) error {
+	key, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+
+	return s.client.RemoveObject(ctx, s.bucket, key, minio.RemoveObjectOptions{})
+}
+
+func (s *s3Store) URL(key string) string {
+	key, err := cleanKey(key)
+	if err != nil {
+		return ""
+	}
+
+	return s.publicURL + "/" + key
+}
+
+func (s *s3Store) SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	signedURL, err := s.client.PresignedGetObject(ctx, s.bucket, key, expiry, url.Values{})
+	if err != nil {
+		return "", err
+	}
+
+	return signedURL.String(), nil
+}
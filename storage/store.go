@@ -0,0 +1,92 @@
+package storage
+
+import (
+	"auth-gorm-echo/config"
+	"context"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrNotFound   = errors.New("File not found")
+	ErrInvalidKey = errors.New("Invalid file key")
+)
+
+// Store menyimpan file upload. Key adalah path relatif, contoh
+// "avatars/abc.jpg", dan key inilah yang disimpan di database.
+type Store interface {
+	Put(ctx context.Context, key string, src io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	// URL adalah URL absolut yang bisa diakses publik
+	URL(key string) string
+	// SignedURL adalah URL sementara, dipakai untuk file yang tidak publik
+	SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error)
+}
+
+// ImmutableCacheControl dipakai untuk semua file upload. Nama file berisi
+// hash isinya dan tidak pernah ditimpa, jadi boleh di-cache selamanya.
+const ImmutableCacheControl = "public, max-age=31536000, immutable"
+
+var defaultStore Store
+
+// NewStore memilih backend dari env STORAGE_DRIVER ("local" atau "s3")
+func NewStore() (Store, error) {
+	publicURL := config.Getenv("APP_URL", "http://localhost:9000")
+
+	switch config.Getenv("STORAGE_DRIVER", "local") {
+	case "local":
+		return NewLocalStore(
+			config.Getenv("STORAGE_LOCAL_DIR", "./images"),
+			config.Getenv("STORAGE_LOCAL_URL", publicURL+"/images"),
+		), nil
+	case "s3":
+		return NewS3Store(S3Config{
+			Endpoint:  config.Getenv("S3_ENDPOINT", "localhost:9100"),
+			AccessKey: config.Getenv("S3_ACCESS_KEY", ""),
+			SecretKey: config.Getenv("S3_SECRET_KEY", ""),
+			Bucket:    config.Getenv("S3_BUCKET", "crowdfunding"),
+			Region:    config.Getenv("S3_REGION", ""),
+			UseSSL:    config.Getenv("S3_USE_SSL", "false") == "true",
+			PublicURL: config.Getenv("S3_PUBLIC_URL", ""),
+		})
+	}
+
+	return nil, errors.New("Unknown STORAGE_DRIVER")
+}
+
+// SetDefault dipanggil di main.go, formatter memakai store ini untuk membuat URL
+func SetDefault(store Store) {
+	defaultStore = store
+}
+
+func GetDefault() Store {
+	return defaultStore
+}
+
+// URL mengubah key yang tersimpan di database menjadi URL absolut
+func URL(key string) string {
+	if key == "" || defaultStore == nil {
+		return key
+	}
+
+	if strings.HasPrefix(key, "http://") || strings.HasPrefix(key, "https://") {
+		return key
+	}
+
+	return defaultStore.URL(key)
+}
+
+func cleanKey(key string) (string, error) {
+	// data lama menyimpan path lengkap, contoh "images/5-cool.png"
+	key = strings.TrimPrefix(strings.TrimPrefix(key, "/"), "images/")
+
+	if key == "" || strings.Contains(key, "..") || strings.Contains(key, "\\") {
+		return "", ErrInvalidKey
+	}
+
+	return key, nil
+}
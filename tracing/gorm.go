@@ -0,0 +1,63 @@
+package tracing
+
+import (
+	"auth-gorm-echo/instrument"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const spanKey = "tracing:span"
+
+// GormPlugin membuat span untuk setiap query, span menjadi child dari context
+// yang dikirim lewat db.WithContext(ctx)
+type GormPlugin struct{}
+
+func (p GormPlugin) Name() string {
+	return "tracing"
+}
+
+func (p GormPlugin) Initialize(db *gorm.DB) error {
+	return instrument.RegisterGormCallbacks(db, "tracing", instrument.GormCallbacks{
+		Before: before,
+		After: func(string) func(*gorm.DB) {
+			return after
+		},
+	})
+}
+
+func before(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		ctx, span := Start(db.Statement.Context, "gorm."+operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(semconv.DBSystemPostgreSQL, semconv.DBOperation(operation)))
+
+		db.Statement.Context = ctx
+		db.InstanceSet(spanKey, span)
+	}
+}
+
+func after(db *gorm.DB) {
+	value, ok := db.InstanceGet(spanKey)
+	if !ok {
+		return
+	}
+
+	span, ok := value.(trace.Span)
+	if !ok {
+		return
+	}
+	defer span.End()
+
+	span.SetAttributes(
+		semconv.DBSQLTable(db.Statement.Table),
+		semconv.DBStatement(db.Statement.SQL.String()),
+		attribute.Int64("db.rows_affected", db.Statement.RowsAffected),
+	)
+
+	if db.Error != gorm.ErrRecordNotFound {
+		RecordError(span, db.Error)
+	}
+}
@@ -0,0 +1,27 @@
+package tracing
+
+import (
+	"auth-gorm-echo/instrument"
+	"context"
+
+	"github.com/redis/go-redis/v9"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RedisHook membuat span untuk setiap command redis
+var RedisHook = instrument.RedisHook{
+	Start: func(ctx context.Context, command string) (context.Context, func(error)) {
+		ctx, span := Start(ctx, "redis."+command,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(semconv.DBSystemRedis, semconv.DBOperation(command)))
+
+		return ctx, func(err error) {
+			if err != redis.Nil {
+				RecordError(span, err)
+			}
+
+			span.End()
+		}
+	},
+}
@@ -0,0 +1,223 @@
+package upload
+
+import (
+	"auth-gorm-echo/apperror"
+	"auth-gorm-echo/storage"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+
+	"golang.org/x/image/draw"
+)
+
+var (
+	ErrMissingFile        = apperror.Validation("upload.missing_file", "File is required")
+	ErrFileTooLarge       = apperror.Validation("upload.file_too_large", "File is too large")
+	ErrUnsupportedType    = apperror.Validation("upload.unsupported_type", "Only JPG/JPEG/PNG image is allowed")
+	ErrInvalidImage       = apperror.Validation("upload.invalid_image", "File is not a valid image")
+	ErrDimensionsTooLarge = apperror.Validation("upload.dimensions_too_large", "Image dimensions are too large")
+	ErrDimensionsTooSmall = apperror.Validation("upload.dimensions_too_small", "Image dimensions are too small")
+)
+
+type Config struct {
+	// Prefix adalah folder di storage, contoh "avatars"
+	Prefix         string
+	MaxBytes       int64
+	MinDimension   int
+	MaxDimension   int
+	ThumbnailSizes []int
+}
+
+// Image adalah hasil upload, Path adalah key gambar utama di storage dan
+// Thumbnails berisi key thumbnail per ukuran
+type Image struct {
+	Path       string
+	Thumbnails map[int]string
+}
+
+type Service interface {
+	SaveImage(ctx context.Context, src io.Reader) (Image, error)
+	DeleteImage(ctx context.Context, path string) error
+}
+
+type service struct {
+	store  storage.Store
+	config Config
+}
+
+func NewService(store storage.Store, config Config) *service {
+	return &service{store, config}
+}
+
+// SaveImage memvalidasi dan meng-encode ulang gambar. Encode ulang sekaligus
+// membuang metadata EXIF dari file asli.
+func (s *service) SaveImage(ctx context.Context, src io.Reader) (Image, error) {
+	result := Image{Thumbnails: map[int]string{}}
+
+	data, err := io.ReadAll(io.LimitReader(src, s.config.MaxBytes+1))
+	if err != nil {
+		return result, err
+	}
+
+	if int64(len(data)) > s.config.MaxBytes {
+		return result, ErrFileTooLarge
+	}
+
+	// cek isi file, bukan nama atau header dari client
+	fileType := http.DetectContentType(data)
+	if fileType != "image/jpeg" && fileType != "image/png" {
+		return result, ErrUnsupportedType
+	}
+
+	// cek dimensi sebelum decode penuh supaya gambar raksasa tidak memenuhi memory
+	imageConfig, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return result, ErrInvalidImage
+	}
+
+	if imageConfig.Width > s.config.MaxDimension || imageConfig.Height > s.config.MaxDimension {
+		return result, ErrDimensionsTooLarge
+	}
+
+	if imageConfig.Width < s.config.MinDimension || imageConfig.Height < s.config.MinDimension {
+		return result, ErrDimensionsTooSmall
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return result, ErrInvalidImage
+	}
+
+	encoded, contentType, err := encodeImage(img, format)
+	if err != nil {
+		return result, err
+	}
+
+	name, err := contentName(encoded.Bytes())
+	if err != nil {
+		return result, err
+	}
+
+	extension := ".jpg"
+	if format == "png" {
+		extension = ".png"
+	}
+
+	result.Path = path.Join(s.config.Prefix, name+extension)
+
+	err = s.store.Put(ctx, result.Path, encoded, int64(encoded.Len()), contentType)
+	if err != nil {
+		return result, err
+	}
+
+	for _, size := range s.config.ThumbnailSizes {
+		thumbnailPath := ThumbnailPath(result.Path, size)
+
+		err = s.writeImage(ctx, thumbnailPath, thumbnail(img, size), format)
+		if err != nil {
+			s.DeleteImage(ctx, result.Path)
+			return result, err
+		}
+
+		result.Thumbnails[size] = thumbnailPath
+	}
+
+	return result, nil
+}
+
+// DeleteImage menghapus gambar beserta thumbnail-nya
+func (s *service) DeleteImage(ctx context.Context, key string) error {
+	if key == "" {
+		return nil
+	}
+
+	keys := []string{key}
+	for _, size := range s.config.ThumbnailSizes {
+		keys = append(keys, ThumbnailPath(key, size))
+	}
+
+	for _, k := range keys {
+		err := s.store.Delete(ctx, k)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ThumbnailPath: avatars/abc.jpg -> avatars/abc_64.jpg
+func ThumbnailPath(key string, size int) string {
+	extension := path.Ext(key)
+	return fmt.Sprintf("%s_%d%s", strings.TrimSuffix(key, extension), size, extension)
+}
+
+// thumbnail memotong gambar menjadi persegi di tengah lalu mengecilkannya
+func thumbnail(img image.Image, size int) image.Image {
+	bounds := img.Bounds()
+
+	side := bounds.Dx()
+	if bounds.Dy() < side {
+		side = bounds.Dy()
+	}
+
+	x := bounds.Min.X + (bounds.Dx()-side)/2
+	y := bounds.Min.Y + (bounds.Dy()-side)/2
+	crop := image.Rect(x, y, x+side, y+side)
+
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, crop, draw.Over, nil)
+
+	return dst
+}
+
+func (s *service) writeImage(ctx context.Context, key string, img image.Image, format string) error {
+	buffer, contentType, err := encodeImage(img, format)
+	if err != nil {
+		return err
+	}
+
+	return s.store.Put(ctx, key, buffer, int64(buffer.Len()), contentType)
+}
+
+func encodeImage(img image.Image, format string) (*bytes.Buffer, string, error) {
+	var buffer bytes.Buffer
+	var err error
+
+	contentType := "image/jpeg"
+
+	if format == "png" {
+		contentType = "image/png"
+		err = png.Encode(&buffer, img)
+	} else {
+		err = jpeg.Encode(&buffer, img, &jpeg.Options{Quality: 90})
+	}
+
+	return &buffer, contentType, err
+}
+
+// contentName: hash isi file supaya nama berganti setiap isi berganti dan
+// aman di-cache immutable, ditambah suffix acak supaya dua user yang upload
+// gambar identik tidak berbagi file (menghapus avatar lama milik satu user
+// tidak boleh menghapus milik user lain)
+func contentName(data []byte) (string, error) {
+	sum := sha256.Sum256(data)
+
+	random := make([]byte, 4)
+	_, err := rand.Read(random)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(sum[:12]) + hex.EncodeToString(random), nil
+}
@@ -10,6 +10,40 @@ type User struct {
 	Password string
 	AvatarFileName string
 	Role string
+	// TOTPSecret terenkripsi dengan APP_KEY, lihat SecretCipher
+	TOTPSecret string
+	TOTPEnabled bool
+	// hanya dibaca, diubah lewat Repository.AcceptTOTPStep supaya Update tidak menimpanya
+	TOTPLastStep int64 `gorm:"->"`
 	CreatedAt time.Time
 	UpdatedAt time.Time
+}
+
+// RecoveryCode disimpan dalam bentuk hash dan hanya bisa dipakai sekali
+type RecoveryCode struct {
+	ID int
+	UserID int
+	CodeHash string
+	UsedAt *time.Time
+	CreatedAt time.Time
+}
+
+func (RecoveryCode) TableName() string {
+	return "user_recovery_codes"
+}
+
+// Identity menghubungkan user dengan akun di provider OAuth (google, github),
+// satu user bisa punya beberapa identity
+type Identity struct {
+	ID int
+	UserID int
+	Provider string
+	Subject string
+	Email string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (Identity) TableName() string {
+	return "user_identities"
 }
\ No newline at end of file
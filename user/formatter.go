@@ -1,5 +1,15 @@
 package user
 
+import (
+	"auth-gorm-echo/storage"
+	"auth-gorm-echo/upload"
+	"encoding/base64"
+	"strconv"
+)
+
+// ukuran thumbnail avatar yang dibuat saat upload
+var AvatarThumbnailSizes = []int{64, 256}
+
 type UserFormatter struct {
 	ID int `json:"id"`
 	Name string `json:"name"`
@@ -7,6 +17,7 @@ type UserFormatter struct {
 	Email string `json:"email"`
 	Token string `json:"token"`
 	ImageURL string `json:"image_url"`
+	ThumbnailURLs map[string]string `json:"thumbnail_urls"`
 }
 
 func FormatUser(user User, token string) UserFormatter {
@@ -16,7 +27,52 @@ func FormatUser(user User, token string) UserFormatter {
 		Occupation: user.Occupation,
 		Email: user.Email,
 		Token: token,
-		ImageURL: user.AvatarFileName,
+		ImageURL: storage.URL(user.AvatarFileName),
+		ThumbnailURLs: FormatAvatarThumbnails(user.AvatarFileName),
+	}
+
+	return formatter
+}
+
+func FormatAvatarThumbnails(avatarFileName string) map[string]string {
+	thumbnails := map[string]string{}
+
+	if avatarFileName == "" {
+		return thumbnails
+	}
+
+	for _, size := range AvatarThumbnailSizes {
+		thumbnails[strconv.Itoa(size)] = storage.URL(upload.ThumbnailPath(avatarFileName, size))
+	}
+
+	return thumbnails
+}
+
+type MFAChallengeFormatter struct {
+	MFARequired bool `json:"mfa_required"`
+	MFAToken string `json:"mfa_token"`
+}
+
+func FormatMFAChallenge(mfaToken string) MFAChallengeFormatter {
+	formatter := MFAChallengeFormatter{
+		MFARequired: true,
+		MFAToken: mfaToken,
+	}
+
+	return formatter
+}
+
+type TwoFactorSetupFormatter struct {
+	Secret string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+	QRCode string `json:"qr_code"`
+}
+
+func FormatTwoFactorSetup(setup TwoFactorSetup) TwoFactorSetupFormatter {
+	formatter := TwoFactorSetupFormatter{
+		Secret: setup.Secret,
+		OTPAuthURI: setup.URI,
+		QRCode: "data:image/png;base64," + base64.StdEncoding.EncodeToString(setup.QRCode),
 	}
 
 	return formatter
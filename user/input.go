@@ -5,13 +5,43 @@ type RegisterUserInput struct {
 	Occupation string `json:"occupation" validate:"required"`
 	Email string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required"`
+	// Role tidak bisa diisi dari request, hanya dari CLI (user create --admin)
+	Role string `json:"-"`
 }
 
 type LoginInput struct {
 	Email string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required"`
+	IP string `json:"-"`
 }
 
 type CheckEmailInput struct {
 	Email string `json:"email" validate:"required,email"`
+}
+
+type TwoFactorCodeInput struct {
+	Code string `json:"code" validate:"required"`
+	IP string `json:"-"`
+}
+
+type TwoFactorLoginInput struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+	Code string `json:"code" validate:"required"`
+	UserID int `json:"-"`
+	IP string `json:"-"`
+}
+
+type OAuthLoginInput struct {
+	Code string `json:"code" validate:"required"`
+	CodeVerifier string `json:"code_verifier" validate:"required,min=43,max=128"`
+	RedirectURI string `json:"redirect_uri" validate:"required,url"`
+}
+
+// OAuthIdentityInput adalah identity yang sudah diverifikasi provider
+type OAuthIdentityInput struct {
+	Provider string
+	Subject string
+	Email string
+	EmailVerified bool
+	Name string
 }
\ No newline at end of file
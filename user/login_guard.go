@@ -0,0 +1,166 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// LoginGuard mencatat login yang gagal per email dan per IP supaya password
+// tidak bisa ditebak tanpa batas
+type LoginGuard interface {
+	// Check mengembalikan *LoginLockedError kalau email / IP sedang diblokir
+	Check(ctx context.Context, email string, ip string) error
+	// Fail mencatat percobaan gagal, locked bernilai true kalau email baru saja dikunci
+	Fail(ctx context.Context, email string, ip string) (locked bool, err error)
+	Reset(ctx context.Context, email string) error
+}
+
+type LoginGuardConfig struct {
+	// jumlah gagal sebelum backoff mulai berlaku
+	BackoffAfter int
+	BaseDelay    time.Duration
+	MaxDelay     time.Duration
+	// jumlah gagal per email sebelum akun dikunci
+	LockThreshold int
+	LockDuration  time.Duration
+	// jumlah gagal per IP sebelum IP diblokir
+	IPThreshold int
+	// lama counter gagal disimpan
+	Window time.Duration
+}
+
+type LoginLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginLockedError) Error() string {
+	return fmt.Sprintf("Too many failed login attempts, try again in %d seconds", int(math.Ceil(e.RetryAfter.Seconds())))
+}
+
+type redisLoginGuard struct {
+	rdb    *redis.Client
+	config LoginGuardConfig
+}
+
+func NewLoginGuard(rdb *redis.Client, config LoginGuardConfig) *redisLoginGuard {
+	return &redisLoginGuard{rdb, config}
+}
+
+func (g *redisLoginGuard) Check(ctx context.Context, email string, ip string) error {
+	keys := []string{
+		g.key("lock", "email", email),
+		g.key("backoff", "email", email),
+		g.key("lock", "ip", ip),
+		g.key("backoff", "ip", ip),
+	}
+
+	var retryAfter time.Duration
+
+	for _, key := range keys {
+		ttl, err := g.rdb.PTTL(ctx, key).Result()
+		if err != nil {
+			return err
+		}
+
+		if ttl > retryAfter {
+			retryAfter = ttl
+		}
+	}
+
+	if retryAfter > 0 {
+		return &LoginLockedError{RetryAfter: retryAfter}
+	}
+
+	return nil
+}
+
+func (g *redisLoginGuard) Fail(ctx context.Context, email string, ip string) (bool, error) {
+	emailFailures, err := g.incr(ctx, g.key("fail", "email", email))
+	if err != nil {
+		return false, err
+	}
+
+	ipFailures, err := g.incr(ctx, g.key("fail", "ip", ip))
+	if err != nil {
+		return false, err
+	}
+
+	if ipFailures >= int64(g.config.IPThreshold) {
+		err = g.rdb.Set(ctx, g.key("lock", "ip", ip), 1, g.config.LockDuration).Err()
+		if err != nil {
+			return false, err
+		}
+	} else if delay := g.delay(ipFailures); delay > 0 {
+		err = g.rdb.Set(ctx, g.key("backoff", "ip", ip), 1, delay).Err()
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if emailFailures >= int64(g.config.LockThreshold) {
+		// SetNX supaya notifikasi hanya dikirim sekali per periode lock
+		locked, err := g.rdb.SetNX(ctx, g.key("lock", "email", email), 1, g.config.LockDuration).Result()
+		if err != nil {
+			return false, err
+		}
+
+		return locked, nil
+	}
+
+	if delay := g.delay(emailFailures); delay > 0 {
+		err = g.rdb.Set(ctx, g.key("backoff", "email", email), 1, delay).Err()
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return false, nil
+}
+
+func (g *redisLoginGuard) Reset(ctx context.Context, email string) error {
+	return g.rdb.Del(ctx, g.key("fail", "email", email), g.key("backoff", "email", email)).Err()
+}
+
+func (g *redisLoginGuard) incr(ctx context.Context, key string) (int64, error) {
+	count, err := g.rdb.Incr(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	if count == 1 {
+		err = g.rdb.Expire(ctx, key, g.config.Window).Err()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return count, nil
+}
+
+// delay menghitung exponential backoff: BaseDelay * 2^(gagal - BackoffAfter)
+func (g *redisLoginGuard) delay(failures int64) time.Duration {
+	exceeded := failures - int64(g.config.BackoffAfter)
+	if exceeded <= 0 {
+		return 0
+	}
+
+	if exceeded > 30 {
+		return g.config.MaxDelay
+	}
+
+	delay := g.config.BaseDelay * time.Duration(1<<uint(exceeded-1))
+	if delay > g.config.MaxDelay {
+		return g.config.MaxDelay
+	}
+
+	return delay
+}
+
+func (g *redisLoginGuard) key(kind string, scope string, value string) string {
+	return fmt.Sprintf("login:%s:%s:%s", kind, scope, strings.ToLower(strings.TrimSpace(value)))
+}
@@ -0,0 +1,89 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestLoginGuardDelay(t *testing.T) {
+	guard := NewLoginGuard(nil, LoginGuardConfig{
+		BackoffAfter: 3,
+		BaseDelay:    time.Second,
+		MaxDelay:     time.Minute,
+	})
+
+	tests := []struct {
+		failures int64
+		want     time.Duration
+	}{
+		{0, 0},
+		{3, 0},
+		{4, time.Second},
+		{5, time.Second * 2},
+		{6, time.Second * 4},
+		{9, time.Second * 32},
+		{10, time.Minute},
+		{40, time.Minute},
+	}
+
+	for _, tt := range tests {
+		got := guard.delay(tt.failures)
+		if got != tt.want {
+			t.Errorf("delay(%d) = %s, want %s", tt.failures, got, tt.want)
+		}
+	}
+}
+
+func TestLoginGuardLocksEmail(t *testing.T) {
+	server := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	ctx := context.Background()
+	guard := NewLoginGuard(rdb, LoginGuardConfig{
+		BackoffAfter:  10,
+		BaseDelay:     time.Second,
+		MaxDelay:      time.Minute,
+		LockThreshold: 3,
+		LockDuration:  time.Minute * 15,
+		IPThreshold:   100,
+		Window:        time.Hour,
+	})
+
+	for i := 1; i <= 3; i++ {
+		locked, err := guard.Fail(ctx, "Budi@example.com", "10.0.0.1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if locked != (i == 3) {
+			t.Fatalf("Fail() #%d locked = %v", i, locked)
+		}
+	}
+
+	// notifikasi lock hanya sekali per periode
+	locked, err := guard.Fail(ctx, "budi@example.com", "10.0.0.2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if locked {
+		t.Fatal("Fail() after lock should not report a new lock")
+	}
+
+	var lockedErr *LoginLockedError
+	err = guard.Check(ctx, "budi@example.com", "10.0.0.3")
+	if !errors.As(err, &lockedErr) || lockedErr.RetryAfter <= 0 || lockedErr.RetryAfter > time.Minute*15 {
+		t.Fatalf("Check() = %v, want LoginLockedError with retry after <= 15m", err)
+	}
+
+	server.FastForward(time.Minute * 15)
+
+	err = guard.Check(ctx, "budi@example.com", "10.0.0.3")
+	if err != nil {
+		t.Fatalf("Check() after lock expired = %v", err)
+	}
+}
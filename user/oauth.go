@@ -0,0 +1,105 @@
+package user
+
+import (
+	"auth-gorm-echo/apperror"
+	"auth-gorm-echo/tracing"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+var ErrUnverifiedEmail = apperror.Validation("oauth.unverified_email", "Email from the provider is not verified")
+
+// LoginWithOAuth mencari user berdasarkan identity provider, kalau belum ada
+// identity tersebut dihubungkan ke user dengan email yang sama atau dibuatkan
+// user baru
+func (s *service) LoginWithOAuth(ctx context.Context, input OAuthIdentityInput) (User, error) {
+	ctx, span := tracing.Start(ctx, "user.Service.LoginWithOAuth")
+	defer span.End()
+
+	identity, err := s.repository.FindIdentity(ctx, input.Provider, input.Subject)
+	if err != nil {
+		return User{}, err
+	}
+
+	if identity.ID != 0 {
+		return s.GetUserByID(ctx, identity.UserID)
+	}
+
+	// menghubungkan akun hanya aman kalau provider sudah memverifikasi email
+	if !input.EmailVerified || input.Email == "" {
+		return User{}, ErrUnverifiedEmail
+	}
+
+	identity = Identity{
+		Provider: input.Provider,
+		Subject:  input.Subject,
+		Email:    input.Email,
+	}
+
+	// email dari provider sudah lowercase, FindByEmail tidak membedakan huruf
+	// besar/kecil sehingga user lama dengan email Foo@x.com tetap terhubung
+	user, err := s.repository.FindByEmail(ctx, input.Email)
+	if err != nil {
+		return user, err
+	}
+
+	if user.ID != 0 {
+		return s.linkIdentity(ctx, user, identity)
+	}
+
+	// user social login tidak punya password, isi dengan password acak
+	random := make([]byte, 32)
+	_, err = rand.Read(random)
+	if err != nil {
+		return User{}, err
+	}
+
+	password, err := bcrypt.GenerateFromPassword([]byte(hex.EncodeToString(random)), bcrypt.MinCost)
+	if err != nil {
+		return User{}, err
+	}
+
+	user = User{}
+	user.Name = input.Name
+	user.Email = input.Email
+	user.Password = string(password)
+	user.Role = "user"
+
+	if user.Name == "" {
+		user.Name = input.Email
+	}
+
+	newUser, err := s.repository.SaveWithIdentity(ctx, user, identity)
+	if errors.Is(err, ErrEmailTaken) {
+		// email didaftarkan request lain setelah FindByEmail di atas
+		user, err = s.repository.FindByEmail(ctx, input.Email)
+		if err != nil {
+			return user, err
+		}
+		if user.ID == 0 {
+			return user, ErrEmailTaken
+		}
+
+		return s.linkIdentity(ctx, user, identity)
+	}
+	if err != nil {
+		return newUser, err
+	}
+
+	return newUser, nil
+}
+
+func (s *service) linkIdentity(ctx context.Context, user User, identity Identity) (User, error) {
+	identity.UserID = user.ID
+
+	_, err := s.repository.SaveIdentity(ctx, identity)
+	if err != nil {
+		return user, err
+	}
+
+	return user, nil
+}
@@ -1,57 +1,275 @@
 package user
 
-import "gorm.io/gorm"
+import (
+	"auth-gorm-echo/tracing"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// index unik LOWER(email), lihat migration 0001
+const emailUniqueIndex = "users_email_key"
 
 type Repository interface {
-	Save(user User) (User, error)
-	FindByEmail(email string) (User, error)
-	FindByID(ID int) (User, error)
-	Update(user User) (User, error)
+	Save(ctx context.Context, user User) (User, error)
+	FindByEmail(ctx context.Context, email string) (User, error)
+	FindByID(ctx context.Context, ID int) (User, error)
+	Update(ctx context.Context, user User) (User, error)
+	EnableTwoFactor(ctx context.Context, userID int, codes []RecoveryCode) error
+	DisableTwoFactor(ctx context.Context, userID int) error
+	UseRecoveryCode(ctx context.Context, userID int, codeHash string) (bool, error)
+	AcceptTOTPStep(ctx context.Context, userID int, step int64) (bool, error)
+	FindIdentity(ctx context.Context, provider string, subject string) (Identity, error)
+	SaveIdentity(ctx context.Context, identity Identity) (Identity, error)
+	SaveWithIdentity(ctx context.Context, user User, identity Identity) (User, error)
 }
 
 type repository struct {
-	db *gorm.DB
+	db      *gorm.DB
+	timeout time.Duration
 }
 
-func NewRepository(db *gorm.DB) *repository {
-	return &repository{db}
+func NewRepository(db *gorm.DB, timeout time.Duration) *repository {
+	return &repository{db, timeout}
 }
 
-func (r *repository) Save(user User) (User, error) {
-	err := r.db.Create(&user).Error
+// withTimeout membatasi lama query supaya query lambat atau client yang
+// sudah disconnect tidak menahan koneksi database
+func (r *repository) withTimeout(ctx context.Context) (*gorm.DB, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	return r.db.WithContext(ctx), cancel
+}
+
+func (r *repository) Save(ctx context.Context, user User) (User, error) {
+	ctx, span := tracing.Start(ctx, "user.Repository.Save")
+	defer span.End()
+
+	db, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	err := db.Create(&user).Error
+	if isEmailTaken(err) {
+		return user, ErrEmailTaken
+	}
 	if err != nil {
+		tracing.RecordError(span, err)
 		return user, err
 	}
 
 	return user, nil
 }
 
-func (r *repository) FindByEmail(email string) (User, error) {
+func (r *repository) FindByEmail(ctx context.Context, email string) (User, error) {
+	ctx, span := tracing.Start(ctx, "user.Repository.FindByEmail")
+	defer span.End()
+
+	db, cancel := r.withTimeout(ctx)
+	defer cancel()
+
 	var user User
-	err := r.db.Where("email = ?", email).Find(&user).Error
+	// sama dengan index unik, Foo@x.com dan foo@x.com adalah user yang sama
+	err := db.Where("LOWER(email) = LOWER(?)", email).Find(&user).Error
 	if err != nil {
+		tracing.RecordError(span, err)
 		return user, err
 	}
 
 	return user, nil
 }
 
-func (r *repository) FindByID(ID int) (User, error) {
+func (r *repository) FindByID(ctx context.Context, ID int) (User, error) {
+	ctx, span := tracing.Start(ctx, "user.Repository.FindByID")
+	defer span.End()
+
+	db, cancel := r.withTimeout(ctx)
+	defer cancel()
+
 	var user User
 
-	err := r.db.Where("id = ?", ID).Find(&user).Error
+	err := db.Where("id = ?", ID).Find(&user).Error
 	if err != nil {
+		tracing.RecordError(span, err)
 		return user, err
 	}
 
 	return user, nil
 }
 
-func (r *repository) Update(user User) (User, error) {
-	err := r.db.Save(&user).Error
+func (r *repository) Update(ctx context.Context, user User) (User, error) {
+	ctx, span := tracing.Start(ctx, "user.Repository.Update")
+	defer span.End()
+
+	db, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	err := db.Save(&user).Error
 	if err != nil {
+		tracing.RecordError(span, err)
 		return user, err
 	}
 
 	return user, nil
-}
\ No newline at end of file
+}
+
+// EnableTwoFactor mengganti recovery code dan mengaktifkan 2FA dalam satu
+// transaksi supaya tidak ada recovery code untuk 2FA yang belum aktif
+func (r *repository) EnableTwoFactor(ctx context.Context, userID int, codes []RecoveryCode) error {
+	ctx, span := tracing.Start(ctx, "user.Repository.EnableTwoFactor")
+	defer span.End()
+
+	db, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("user_id = ?", userID).Delete(&RecoveryCode{}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Create(&codes).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&User{}).Where("id = ?", userID).Update("totp_enabled", true).Error
+	})
+	tracing.RecordError(span, err)
+
+	return err
+}
+
+// DisableTwoFactor menghapus secret dan recovery code dalam satu transaksi
+func (r *repository) DisableTwoFactor(ctx context.Context, userID int) error {
+	ctx, span := tracing.Start(ctx, "user.Repository.DisableTwoFactor")
+	defer span.End()
+
+	db, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("user_id = ?", userID).Delete(&RecoveryCode{}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+			"totp_secret":  "",
+			"totp_enabled": false,
+		}).Error
+	})
+	tracing.RecordError(span, err)
+
+	return err
+}
+
+// UseRecoveryCode menandai code sebagai terpakai, false kalau code tidak ada atau sudah dipakai
+func (r *repository) UseRecoveryCode(ctx context.Context, userID int, codeHash string) (bool, error) {
+	ctx, span := tracing.Start(ctx, "user.Repository.UseRecoveryCode")
+	defer span.End()
+
+	db, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	result := db.Model(&RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		tracing.RecordError(span, result.Error)
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// AcceptTOTPStep mencatat time step kode TOTP yang dipakai, false kalau
+// step yang sama atau yang lebih baru sudah pernah diterima (replay)
+func (r *repository) AcceptTOTPStep(ctx context.Context, userID int, step int64) (bool, error) {
+	ctx, span := tracing.Start(ctx, "user.Repository.AcceptTOTPStep")
+	defer span.End()
+
+	db, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	// lewat Table karena kolom ini read-only di struct User
+	result := db.Table("users").
+		Where("id = ? AND totp_last_step < ?", userID, step).
+		Update("totp_last_step", step)
+	if result.Error != nil {
+		tracing.RecordError(span, result.Error)
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+func (r *repository) FindIdentity(ctx context.Context, provider string, subject string) (Identity, error) {
+	ctx, span := tracing.Start(ctx, "user.Repository.FindIdentity")
+	defer span.End()
+
+	db, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	var identity Identity
+
+	err := db.Where("provider = ? AND subject = ?", provider, subject).Find(&identity).Error
+	if err != nil {
+		tracing.RecordError(span, err)
+		return identity, err
+	}
+
+	return identity, nil
+}
+
+func (r *repository) SaveIdentity(ctx context.Context, identity Identity) (Identity, error) {
+	ctx, span := tracing.Start(ctx, "user.Repository.SaveIdentity")
+	defer span.End()
+
+	db, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	err := db.Create(&identity).Error
+	if err != nil {
+		tracing.RecordError(span, err)
+		return identity, err
+	}
+
+	return identity, nil
+}
+
+// SaveWithIdentity membuat user baru beserta identity-nya dalam satu transaksi
+func (r *repository) SaveWithIdentity(ctx context.Context, user User, identity Identity) (User, error) {
+	ctx, span := tracing.Start(ctx, "user.Repository.SaveWithIdentity")
+	defer span.End()
+
+	db, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Create(&user).Error
+		if err != nil {
+			return err
+		}
+
+		identity.UserID = user.ID
+		return tx.Create(&identity).Error
+	})
+	if isEmailTaken(err) {
+		return user, ErrEmailTaken
+	}
+	if err != nil {
+		tracing.RecordError(span, err)
+		return user, err
+	}
+
+	return user, nil
+}
+
+// isEmailTaken mengenali insert yang kalah balapan dengan registrasi lain
+// untuk email yang sama setelah pengecekan FindByEmail
+func isEmailTaken(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == emailUniqueIndex
+}
@@ -1,28 +1,72 @@
 package user
 
 import (
-	"github.com/pkg/errors"
+	"auth-gorm-echo/apperror"
+	"auth-gorm-echo/mailer"
+	"auth-gorm-echo/metrics"
+	"auth-gorm-echo/tracing"
+	"context"
+	"fmt"
+	"log/slog"
+
 	"golang.org/x/crypto/bcrypt"
 )
 
+var (
+	ErrInvalidCredentials = apperror.Unauthorized("auth.invalid_credentials", "Invalid email or password")
+	ErrUserNotFound       = apperror.NotFound("user.not_found", "No user found on that ID")
+	ErrEmailTaken         = apperror.Conflict("user.email_taken", "Email has been registered")
+)
+
+// dummyPassword dipakai untuk bcrypt compare saat email tidak ditemukan,
+// supaya waktu respon login sama untuk email yang ada maupun tidak
+var dummyPassword, _ = bcrypt.GenerateFromPassword([]byte("dummy-password"), bcrypt.MinCost)
+
 type Service interface {
-	RegisterUser(input RegisterUserInput) (User, error)
-	Login(input LoginInput) (User, error)
-	IsEmailAvailable(input CheckEmailInput) (bool, error)
-	GetUserByID(ID int) (User, error)
-	SaveAvatar(ID int, fileLocation string) (User, error)
+	RegisterUser(ctx context.Context, input RegisterUserInput) (User, error)
+	Login(ctx context.Context, input LoginInput) (User, error)
+	IsEmailAvailable(ctx context.Context, input CheckEmailInput) (bool, error)
+	GetUserByID(ctx context.Context, ID int) (User, error)
+	SaveAvatar(ctx context.Context, ID int, fileLocation string) (User, error)
+	SetupTwoFactor(ctx context.Context, ID int) (TwoFactorSetup, error)
+	ConfirmTwoFactor(ctx context.Context, ID int, input TwoFactorCodeInput) ([]string, error)
+	DisableTwoFactor(ctx context.Context, ID int, input TwoFactorCodeInput) error
+	VerifyTwoFactorLogin(ctx context.Context, input TwoFactorLoginInput) (User, error)
+	LoginWithOAuth(ctx context.Context, input OAuthIdentityInput) (User, error)
+}
+
+// SecretCipher mengenkripsi secret TOTP sebelum disimpan ke database
+type SecretCipher interface {
+	Encrypt(plaintext string) (string, error)
+	Decrypt(value string) (string, error)
 }
 
 type service struct {
 	repository Repository
+	loginGuard LoginGuard
+	cipher     SecretCipher
+	mailer     mailer.Mailer
+	logger     *slog.Logger
 }
 
-func NewService(repository Repository) *service {
-	return &service{repository}
+func NewService(repository Repository, loginGuard LoginGuard, cipher SecretCipher, mailer mailer.Mailer, logger *slog.Logger) *service {
+	return &service{repository, loginGuard, cipher, mailer, logger}
 }
 
 // RegisterUser
-func (s *service) RegisterUser(input RegisterUserInput) (User, error) {
+func (s *service) RegisterUser(ctx context.Context, input RegisterUserInput) (User, error) {
+	ctx, span := tracing.Start(ctx, "user.Service.RegisterUser")
+	defer span.End()
+
+	existingUser, err := s.repository.FindByEmail(ctx, input.Email)
+	if err != nil {
+		return existingUser, err
+	}
+
+	if existingUser.ID != 0 {
+		return User{}, ErrEmailTaken
+	}
+
 	user := User{}
 	user.Name = input.Name
 	user.Email = input.Email
@@ -36,7 +80,11 @@ func (s *service) RegisterUser(input RegisterUserInput) (User, error) {
 	user.Password = string(password)
 	user.Role = "user"
 
-	newUser, err := s.repository.Save(user)
+	if input.Role != "" {
+		user.Role = input.Role
+	}
+
+	newUser, err := s.repository.Save(ctx, user)
 	if err != nil {
 		return newUser, err
 	}
@@ -45,20 +93,46 @@ func (s *service) RegisterUser(input RegisterUserInput) (User, error) {
 }
 
 // Login
-func (s *service) Login(input LoginInput) (User, error) {
+func (s *service) Login(ctx context.Context, input LoginInput) (User, error) {
+	ctx, span := tracing.Start(ctx, "user.Service.Login")
+	defer span.End()
+
 	email := input.Email
 	password := input.Password
 
-	user, err := s.repository.FindByEmail(email)
+	err := s.loginGuard.Check(ctx, email, input.IP)
+	if err != nil {
+		metrics.FailedLogins.WithLabelValues("locked").Inc()
+		return User{}, err
+	}
+
+	user, err := s.repository.FindByEmail(ctx, email)
 	if err != nil {
 		return user, err
 	}
 
-	if user.ID == 0 {
-		return user, errors.New("No user found on that email")
+	hashedPassword := dummyPassword
+	if user.ID != 0 {
+		hashedPassword = []byte(user.Password)
+	}
+
+	err = bcrypt.CompareHashAndPassword(hashedPassword, []byte(password))
+	if err != nil || user.ID == 0 {
+		// email yang tidak terdaftar juga dihitung supaya lockout tidak membocorkan email
+		locked, guardErr := s.loginGuard.Fail(ctx, email, input.IP)
+		if guardErr != nil {
+			return User{}, guardErr
+		}
+
+		if locked && user.ID != 0 {
+			s.notifyLocked(ctx, user)
+		}
+
+		metrics.FailedLogins.WithLabelValues("invalid_credentials").Inc()
+		return User{}, ErrInvalidCredentials
 	}
 
-	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
+	err = s.loginGuard.Reset(ctx, email)
 	if err != nil {
 		return user, err
 	}
@@ -66,10 +140,26 @@ func (s *service) Login(input LoginInput) (User, error) {
 	return user, nil
 }
 
-func (s *service) IsEmailAvailable(input CheckEmailInput) (bool, error) {
+func (s *service) notifyLocked(ctx context.Context, user User) {
+	s.logger.WarnContext(ctx, "account locked after failed login attempts", "locked_user_id", user.ID)
+
+	subject := "Your account has been temporarily locked"
+	body := fmt.Sprintf("Hi %s,\n\nWe noticed several failed login attempts on your account, so we have temporarily locked it. "+
+		"If this was not you, we recommend changing your password once the lock expires.", user.Name)
+
+	err := s.mailer.Send(user.Email, subject, body)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to send lockout email", "locked_user_id", user.ID, "error", err)
+	}
+}
+
+func (s *service) IsEmailAvailable(ctx context.Context, input CheckEmailInput) (bool, error) {
+	ctx, span := tracing.Start(ctx, "user.Service.IsEmailAvailable")
+	defer span.End()
+
 	email := input.Email
 
-	user, err := s.repository.FindByEmail(email)
+	user, err := s.repository.FindByEmail(ctx, email)
 	if err != nil {
 		return false, err
 	}
@@ -82,14 +172,17 @@ func (s *service) IsEmailAvailable(input CheckEmailInput) (bool, error) {
 }
 
 // Get User By ID
-func (s *service) GetUserByID(ID int) (User, error) {
-	user, err := s.repository.FindByID(ID)
+func (s *service) GetUserByID(ctx context.Context, ID int) (User, error) {
+	ctx, span := tracing.Start(ctx, "user.Service.GetUserByID")
+	defer span.End()
+
+	user, err := s.repository.FindByID(ctx, ID)
 	if err != nil {
 		return user, err
 	}
 
 	if user.ID == 0 {
-		return user, errors.New("No user found on that ID")
+		return user, ErrUserNotFound
 	}
 
 	return user, nil
@@ -99,19 +192,22 @@ func (s *service) GetUserByID(ID int) (User, error) {
 // simpan struct User melalui repository
 
 // SaveAvatar
-func (s *service) SaveAvatar(ID int, fileLocation string) (User, error) {
+func (s *service) SaveAvatar(ctx context.Context, ID int, fileLocation string) (User, error) {
+	ctx, span := tracing.Start(ctx, "user.Service.SaveAvatar")
+	defer span.End()
+
 	// dapatkan user berdasarkan ID
 	// update attribute avatar file name
 	// simpan perubahan avatar file name ke database
 
-	user, err := s.repository.FindByID(ID)
+	user, err := s.repository.FindByID(ctx, ID)
 	if err != nil {
 		return user, err
 	}
 
 	user.AvatarFileName = fileLocation
 
-	updatedUser, err := s.repository.Update(user)
+	updatedUser, err := s.repository.Update(ctx, user)
 	if err != nil {
 		return updatedUser, err
 	}
@@ -0,0 +1,338 @@
+package user
+
+import (
+	"auth-gorm-echo/apperror"
+	"auth-gorm-echo/config"
+	"auth-gorm-echo/encryption"
+	"auth-gorm-echo/metrics"
+	"auth-gorm-echo/tracing"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/hex"
+	"image/png"
+	"strings"
+	"time"
+
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+)
+
+const recoveryCodeCount = 10
+
+// sama dengan default totp.Generate / totp.Validate
+const (
+	totpPeriod = 30
+	totpSkew   = 1
+)
+
+var (
+	ErrTwoFactorAlreadyEnabled = apperror.Conflict("2fa.already_enabled", "Two-factor authentication is already enabled")
+	ErrTwoFactorNotSetup       = apperror.Validation("2fa.not_setup", "Two-factor authentication has not been set up")
+	ErrInvalidTwoFactorCode    = apperror.Validation("2fa.invalid_code", "Invalid two-factor authentication code")
+)
+
+type TwoFactorSetup struct {
+	Secret string
+	URI    string
+	QRCode []byte
+}
+
+// SetupTwoFactor membuat secret TOTP baru, 2FA baru aktif setelah dikonfirmasi
+func (s *service) SetupTwoFactor(ctx context.Context, ID int) (TwoFactorSetup, error) {
+	ctx, span := tracing.Start(ctx, "user.Service.SetupTwoFactor")
+	defer span.End()
+
+	setup := TwoFactorSetup{}
+
+	user, err := s.GetUserByID(ctx, ID)
+	if err != nil {
+		return setup, err
+	}
+
+	if user.TOTPEnabled {
+		return setup, ErrTwoFactorAlreadyEnabled
+	}
+
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      config.Getenv("TOTP_ISSUER", "Crowdfunding"),
+		AccountName: user.Email,
+	})
+	if err != nil {
+		return setup, err
+	}
+
+	image, err := key.Image(256, 256)
+	if err != nil {
+		return setup, err
+	}
+
+	var qrCode bytes.Buffer
+	err = png.Encode(&qrCode, image)
+	if err != nil {
+		return setup, err
+	}
+
+	user.TOTPSecret, err = s.cipher.Encrypt(key.Secret())
+	if err != nil {
+		return setup, err
+	}
+
+	_, err = s.repository.Update(ctx, user)
+	if err != nil {
+		return setup, err
+	}
+
+	setup.Secret = key.Secret()
+	setup.URI = key.URL()
+	setup.QRCode = qrCode.Bytes()
+
+	return setup, nil
+}
+
+// ConfirmTwoFactor mengaktifkan 2FA dan mengembalikan recovery code dalam
+// bentuk plain text, code ini hanya ditampilkan sekali
+func (s *service) ConfirmTwoFactor(ctx context.Context, ID int, input TwoFactorCodeInput) ([]string, error) {
+	ctx, span := tracing.Start(ctx, "user.Service.ConfirmTwoFactor")
+	defer span.End()
+
+	user, err := s.GetUserByID(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.TOTPEnabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	if user.TOTPSecret == "" {
+		return nil, ErrTwoFactorNotSetup
+	}
+
+	secret, err := s.cipher.Decrypt(user.TOTPSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	step, valid := matchTOTP(input.Code, secret, time.Now())
+	if !valid {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	accepted, err := s.repository.AcceptTOTPStep(ctx, user.ID, step)
+	if err != nil {
+		return nil, err
+	}
+
+	if !accepted {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	codes, recoveryCodes, err := generateRecoveryCodes(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repository.EnableTwoFactor(ctx, user.ID, recoveryCodes)
+	if err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// DisableTwoFactor memakai login guard yang sama dengan login supaya pemegang
+// token curian tidak bisa menebak kode tanpa batas
+func (s *service) DisableTwoFactor(ctx context.Context, ID int, input TwoFactorCodeInput) error {
+	ctx, span := tracing.Start(ctx, "user.Service.DisableTwoFactor")
+	defer span.End()
+
+	user, err := s.GetUserByID(ctx, ID)
+	if err != nil {
+		return err
+	}
+
+	if !user.TOTPEnabled {
+		return ErrTwoFactorNotSetup
+	}
+
+	err = s.loginGuard.Check(ctx, user.Email, input.IP)
+	if err != nil {
+		metrics.FailedLogins.WithLabelValues("locked").Inc()
+		return err
+	}
+
+	valid, err := s.checkTwoFactorCode(ctx, user, input.Code)
+	if err != nil {
+		return err
+	}
+
+	if !valid {
+		locked, err := s.loginGuard.Fail(ctx, user.Email, input.IP)
+		if err != nil {
+			return err
+		}
+
+		if locked {
+			s.notifyLocked(ctx, user)
+		}
+
+		metrics.FailedLogins.WithLabelValues("invalid_2fa_code").Inc()
+		return ErrInvalidTwoFactorCode
+	}
+
+	err = s.loginGuard.Reset(ctx, user.Email)
+	if err != nil {
+		return err
+	}
+
+	err = s.repository.DisableTwoFactor(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// VerifyTwoFactorLogin adalah langkah kedua login setelah password benar
+func (s *service) VerifyTwoFactorLogin(ctx context.Context, input TwoFactorLoginInput) (User, error) {
+	ctx, span := tracing.Start(ctx, "user.Service.VerifyTwoFactorLogin")
+	defer span.End()
+
+	user, err := s.GetUserByID(ctx, input.UserID)
+	if err != nil {
+		return User{}, err
+	}
+
+	err = s.loginGuard.Check(ctx, user.Email, input.IP)
+	if err != nil {
+		metrics.FailedLogins.WithLabelValues("locked").Inc()
+		return User{}, err
+	}
+
+	valid, err := s.checkTwoFactorCode(ctx, user, input.Code)
+	if err != nil {
+		return User{}, err
+	}
+
+	if !valid {
+		locked, err := s.loginGuard.Fail(ctx, user.Email, input.IP)
+		if err != nil {
+			return User{}, err
+		}
+
+		if locked {
+			s.notifyLocked(ctx, user)
+		}
+
+		metrics.FailedLogins.WithLabelValues("invalid_2fa_code").Inc()
+		return User{}, ErrInvalidTwoFactorCode
+	}
+
+	err = s.loginGuard.Reset(ctx, user.Email)
+	if err != nil {
+		return user, err
+	}
+
+	return user, nil
+}
+
+// checkTwoFactorCode menerima kode TOTP atau recovery code. Kode TOTP yang
+// sudah pernah dipakai ditolak walaupun masih dalam masa berlakunya.
+func (s *service) checkTwoFactorCode(ctx context.Context, user User, code string) (bool, error) {
+	if !user.TOTPEnabled || user.TOTPSecret == "" {
+		return false, nil
+	}
+
+	secret, err := s.cipher.Decrypt(user.TOTPSecret)
+	if err != nil {
+		return false, err
+	}
+
+	step, valid := matchTOTP(code, secret, time.Now())
+	if valid {
+		accepted, err := s.repository.AcceptTOTPStep(ctx, user.ID, step)
+		if err != nil || !accepted {
+			return false, err
+		}
+
+		// secret yang disimpan sebelum enkripsi diterapkan dienkripsi saat dipakai
+		if !encryption.IsEncrypted(user.TOTPSecret) {
+			s.encryptLegacySecret(ctx, user, secret)
+		}
+
+		return true, nil
+	}
+
+	return s.repository.UseRecoveryCode(ctx, user.ID, hashRecoveryCode(code))
+}
+
+func (s *service) encryptLegacySecret(ctx context.Context, user User, secret string) {
+	encrypted, err := s.cipher.Encrypt(secret)
+	if err == nil {
+		user.TOTPSecret = encrypted
+		_, err = s.repository.Update(ctx, user)
+	}
+
+	if err != nil {
+		s.logger.WarnContext(ctx, "failed to encrypt legacy totp secret", "user_id", user.ID, "error", err)
+	}
+}
+
+// matchTOTP sama dengan totp.Validate tapi juga mengembalikan time step kode yang cocok
+func matchTOTP(code string, secret string, now time.Time) (int64, bool) {
+	opts := totp.ValidateOpts{
+		Period:    totpPeriod,
+		Digits:    otp.DigitsSix,
+		Algorithm: otp.AlgorithmSHA1,
+	}
+
+	for i := -totpSkew; i <= totpSkew; i++ {
+		t := now.Add(time.Duration(i*totpPeriod) * time.Second)
+
+		expected, err := totp.GenerateCodeCustom(secret, t, opts)
+		if err != nil {
+			return 0, false
+		}
+
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return t.Unix() / totpPeriod, true
+		}
+	}
+
+	return 0, false
+}
+
+func generateRecoveryCodes(userID int) ([]string, []RecoveryCode, error) {
+	var codes []string
+	var recoveryCodes []RecoveryCode
+
+	for i := 0; i < recoveryCodeCount; i++ {
+		random := make([]byte, 5)
+		_, err := rand.Read(random)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		code := strings.ToLower(base32.StdEncoding.EncodeToString(random))
+		code = code[:4] + "-" + code[4:]
+
+		codes = append(codes, code)
+		recoveryCodes = append(recoveryCodes, RecoveryCode{
+			UserID:   userID,
+			CodeHash: hashRecoveryCode(code),
+		})
+	}
+
+	return codes, recoveryCodes, nil
+}
+
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	hash := sha256.Sum256([]byte(normalized))
+
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,118 @@
+package user
+
+import (
+	"auth-gorm-echo/encryption"
+	"auth-gorm-echo/logging"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+)
+
+const testTOTPSecret = "JBSWY3DPEHPK3PXP"
+
+func generateTOTP(t *testing.T, at time.Time) string {
+	t.Helper()
+
+	code, err := totp.GenerateCodeCustom(testTOTPSecret, at, totp.ValidateOpts{
+		Period:    totpPeriod,
+		Digits:    otp.DigitsSix,
+		Algorithm: otp.AlgorithmSHA1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return code
+}
+
+func TestMatchTOTP(t *testing.T) {
+	now := time.Unix(1700000010, 0)
+	step := now.Unix() / totpPeriod
+
+	tests := []struct {
+		name     string
+		at       time.Time
+		wantStep int64
+		wantOK   bool
+	}{
+		{"step sekarang", now, step, true},
+		{"step sebelumnya masih diterima", now.Add(-totpPeriod * time.Second), step - 1, true},
+		{"step berikutnya masih diterima", now.Add(totpPeriod * time.Second), step + 1, true},
+		{"dua step lalu ditolak", now.Add(-2 * totpPeriod * time.Second), 0, false},
+		{"dua step lagi ditolak", now.Add(2 * totpPeriod * time.Second), 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotStep, gotOK := matchTOTP(generateTOTP(t, tt.at), testTOTPSecret, now)
+			if gotStep != tt.wantStep || gotOK != tt.wantOK {
+				t.Errorf("matchTOTP() = (%d, %v), want (%d, %v)", gotStep, gotOK, tt.wantStep, tt.wantOK)
+			}
+		})
+	}
+}
+
+// totpRepository meniru AcceptTOTPStep di database: step hanya diterima
+// kalau lebih baru dari step terakhir
+type totpRepository struct {
+	Repository
+	lastStep int64
+}
+
+func (r *totpRepository) AcceptTOTPStep(ctx context.Context, userID int, step int64) (bool, error) {
+	if step <= r.lastStep {
+		return false, nil
+	}
+
+	r.lastStep = step
+	return true, nil
+}
+
+func (r *totpRepository) UseRecoveryCode(ctx context.Context, userID int, codeHash string) (bool, error) {
+	return false, nil
+}
+
+func TestCheckTwoFactorCodeRejectsReplay(t *testing.T) {
+	cipher, err := encryption.NewCipher(make([]byte, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	secret, err := cipher.Encrypt(testTOTPSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewService(&totpRepository{}, nil, cipher, nil, logging.New())
+	user := User{ID: 1, TOTPEnabled: true, TOTPSecret: secret}
+
+	now := time.Now()
+	code := generateTOTP(t, now)
+	previous := generateTOTP(t, now.Add(-totpPeriod*time.Second))
+
+	tests := []struct {
+		name string
+		code string
+		want bool
+	}{
+		{"kode pertama diterima", code, true},
+		{"kode yang sama ditolak", code, false},
+		{"kode step lebih lama ditolak", previous, false},
+		{"kode salah ditolak", "000000", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.checkTwoFactorCode(context.Background(), user, tt.code)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("checkTwoFactorCode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
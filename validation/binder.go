@@ -0,0 +1,65 @@
+package validation
+
+import (
+	"auth-gorm-echo/apperror"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+var (
+	ErrMalformedBody    = apperror.BadRequest("request.malformed_body", "Request body is not valid JSON")
+	ErrInvalidParameter = apperror.BadRequest("request.invalid_parameter", "Request contains an invalid parameter")
+)
+
+// Binder membungkus echo.DefaultBinder supaya error decode dikembalikan
+// sebagai error yang bisa dibaca client, bukan pesan mentah dari encoding/json
+type Binder struct {
+	echo.DefaultBinder
+}
+
+func (b *Binder) Bind(i interface{}, c echo.Context) error {
+	err := b.DefaultBinder.Bind(i, c)
+	if err == nil {
+		return nil
+	}
+
+	var typeError *json.UnmarshalTypeError
+	var syntaxError *json.SyntaxError
+	var numError *strconv.NumError
+
+	switch {
+	case errors.As(err, &typeError):
+		field := typeError.Field
+		if field == "" {
+			field = "body"
+		}
+
+		return &Error{typeErrors: map[string]string{field: jsonType(typeError.Type.Kind().String())}}
+	case errors.As(err, &syntaxError), errors.Is(err, io.ErrUnexpectedEOF), errors.Is(err, io.EOF):
+		return ErrMalformedBody
+	case errors.As(err, &numError):
+		return ErrInvalidParameter
+	}
+
+	// contoh: content type tidak didukung (415)
+	return err
+}
+
+func jsonType(kind string) string {
+	switch kind {
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "float32", "float64":
+		return "number"
+	case "bool":
+		return "boolean"
+	case "slice", "array":
+		return "array"
+	case "struct", "map":
+		return "object"
+	}
+
+	return kind
+}
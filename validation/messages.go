@@ -0,0 +1,37 @@
+package validation
+
+import (
+	ut "github.com/go-playground/universal-translator"
+)
+
+// pesan yang tidak disediakan oleh translations bawaan validator
+var messages = map[string]map[string]string{
+	"en": {
+		"type_mismatch": "{0} must be a valid {1}",
+	},
+	"id": {
+		"type_mismatch": "{0} harus berupa {1} yang valid",
+	},
+}
+
+func init() {
+	for locale, catalog := range messages {
+		translator, _ := universalTranslator.GetTranslator(locale)
+
+		for key, text := range catalog {
+			err := translator.Add(key, text, false)
+			if err != nil {
+				panic(err)
+			}
+		}
+	}
+}
+
+func translate(translator ut.Translator, key string, params ...string) string {
+	text, err := translator.T(key, params...)
+	if err != nil {
+		return key
+	}
+
+	return text
+}
@@ -0,0 +1,104 @@
+package validation
+
+import (
+	"auth-gorm-echo/apperror"
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/id"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	enTranslations "github.com/go-playground/validator/v10/translations/en"
+	idTranslations "github.com/go-playground/validator/v10/translations/id"
+)
+
+var universalTranslator = ut.New(en.New(), en.New(), id.New())
+
+// Validator dipasang sebagai echo.Validator
+type Validator struct {
+	validate *validator.Validate
+}
+
+func New() *Validator {
+	validate := validator.New()
+
+	// nama field di pesan error mengikuti nama yang dikirim client
+	validate.RegisterTagNameFunc(fieldName)
+
+	enTranslator, _ := universalTranslator.GetTranslator("en")
+	idTranslator, _ := universalTranslator.GetTranslator("id")
+
+	err := enTranslations.RegisterDefaultTranslations(validate, enTranslator)
+	if err != nil {
+		panic(err)
+	}
+
+	err = idTranslations.RegisterDefaultTranslations(validate, idTranslator)
+	if err != nil {
+		panic(err)
+	}
+
+	return &Validator{validate}
+}
+
+func (v *Validator) Validate(i interface{}) error {
+	err := v.validate.Struct(i)
+
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		return &Error{validationErrors: validationErrors}
+	}
+
+	return err
+}
+
+// Error berisi kesalahan per field, pesannya diterjemahkan saat response
+// dibuat supaya mengikuti bahasa client
+type Error struct {
+	validationErrors validator.ValidationErrors
+	// kesalahan tipe data dari body JSON, key adalah nama field
+	typeErrors map[string]string
+}
+
+func (e *Error) Error() string {
+	return apperror.ErrValidation.Error()
+}
+
+func (e *Error) Is(target error) bool {
+	return target == apperror.ErrValidation
+}
+
+// Fields mengembalikan pesan error per field dalam bahasa translator
+func (e *Error) Fields(translator ut.Translator) map[string]string {
+	fields := map[string]string{}
+
+	for _, fieldError := range e.validationErrors {
+		fields[fieldError.Field()] = fieldError.Translate(translator)
+	}
+
+	for field, expected := range e.typeErrors {
+		fields[field] = translate(translator, "type_mismatch", field, expected)
+	}
+
+	return fields
+}
+
+// Translator mengembalikan translator untuk bahasa hasil negosiasi
+// i18n.Middleware, bahasa yang tidak didukung memakai bahasa Inggris
+func Translator(locale string) ut.Translator {
+	translator, _ := universalTranslator.GetTranslator(locale)
+	return translator
+}
+
+func fieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "form", "param", "query"} {
+		name := strings.SplitN(field.Tag.Get(tag), ",", 2)[0]
+		if name != "" && name != "-" {
+			return name
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,67 @@
+package webhook
+
+import (
+	"strings"
+	"time"
+)
+
+// event yang bisa di-subscribe creator, harus sama dengan oneof di CreateEndpointInput
+const (
+	EventPledgePaid     = "pledge.paid"
+	EventPledgeRefunded = "pledge.refunded"
+	EventCampaignFunded = "campaign.funded"
+	EventCampaignClosed = "campaign.closed"
+	// EventTest hanya dikirim lewat endpoint "send test event"
+	EventTest = "webhook.test"
+)
+
+type Endpoint struct {
+	ID     int
+	UserID int
+	URL    string
+	// Secret dipakai untuk tanda tangan HMAC, disimpan terenkripsi dengan APP_KEY
+	Secret    string
+	Events    string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (Endpoint) TableName() string {
+	return "webhook_endpoints"
+}
+
+func (e Endpoint) EventList() []string {
+	if e.Events == "" {
+		return []string{}
+	}
+
+	return strings.Split(e.Events, ",")
+}
+
+func (e Endpoint) IsSubscribed(eventType string) bool {
+	for _, event := range e.EventList() {
+		if event == eventType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Delivery mencatat satu percobaan pengiriman
+type Delivery struct {
+	ID           int64
+	EndpointID   int
+	EventID      string
+	EventType    string
+	Attempt      int
+	StatusCode   int
+	ResponseBody string
+	Error        string
+	DurationMs   int
+	CreatedAt    time.Time
+}
+
+func (Delivery) TableName() string {
+	return "webhook_deliveries"
+}
@@ -0,0 +1,78 @@
+package webhook
+
+import "time"
+
+type EndpointFormatter struct {
+	ID        int       `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CreatedEndpointFormatter hanya dipakai saat endpoint dibuat karena memuat secret
+type CreatedEndpointFormatter struct {
+	EndpointFormatter
+	Secret string `json:"secret"`
+}
+
+type DeliveryFormatter struct {
+	ID           int64     `json:"id"`
+	EventID      string    `json:"event_id"`
+	EventType    string    `json:"event_type"`
+	Attempt      int       `json:"attempt"`
+	StatusCode   int       `json:"status_code"`
+	ResponseBody string    `json:"response_body"`
+	Error        string    `json:"error"`
+	DurationMs   int       `json:"duration_ms"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func FormatEndpoint(endpoint Endpoint) EndpointFormatter {
+	formatter := EndpointFormatter{
+		ID:        endpoint.ID,
+		URL:       endpoint.URL,
+		Events:    endpoint.EventList(),
+		CreatedAt: endpoint.CreatedAt,
+	}
+
+	return formatter
+}
+
+func FormatEndpoints(endpoints []Endpoint) []EndpointFormatter {
+	endpointsFormatter := []EndpointFormatter{}
+
+	for _, endpoint := range endpoints {
+		endpointsFormatter = append(endpointsFormatter, FormatEndpoint(endpoint))
+	}
+
+	return endpointsFormatter
+}
+
+func FormatCreatedEndpoint(endpoint Endpoint) CreatedEndpointFormatter {
+	formatter := CreatedEndpointFormatter{
+		EndpointFormatter: FormatEndpoint(endpoint),
+		Secret:            endpoint.Secret,
+	}
+
+	return formatter
+}
+
+func FormatDeliveries(deliveries []Delivery) []DeliveryFormatter {
+	deliveriesFormatter := []DeliveryFormatter{}
+
+	for _, delivery := range deliveries {
+		deliveriesFormatter = append(deliveriesFormatter, DeliveryFormatter{
+			ID:           delivery.ID,
+			EventID:      delivery.EventID,
+			EventType:    delivery.EventType,
+			Attempt:      delivery.Attempt,
+			StatusCode:   delivery.StatusCode,
+			ResponseBody: delivery.ResponseBody,
+			Error:        delivery.Error,
+			DurationMs:   delivery.DurationMs,
+			CreatedAt:    delivery.CreatedAt,
+		})
+	}
+
+	return deliveriesFormatter
+}
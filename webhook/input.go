@@ -0,0 +1,15 @@
+package webhook
+
+import "auth-gorm-echo/user"
+
+type CreateEndpointInput struct {
+	URL    string   `json:"url" validate:"required,url,max=2048"`
+	Events []string `json:"events" validate:"required,min=1,dive,oneof=pledge.paid pledge.refunded campaign.funded campaign.closed"`
+	User   user.User
+}
+
+// EndpointInput dipakai route /users/me/webhooks/:id
+type EndpointInput struct {
+	ID   int `param:"id" validate:"required"`
+	User user.User
+}
@@ -0,0 +1,117 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	Save(ctx context.Context, endpoint Endpoint) (Endpoint, error)
+	FindByID(ctx context.Context, ID int) (Endpoint, error)
+	FindByUserID(ctx context.Context, userID int) ([]Endpoint, error)
+	Delete(ctx context.Context, ID int, userID int) (bool, error)
+	UpdateSecret(ctx context.Context, ID int, secret string) error
+	SaveDelivery(ctx context.Context, delivery Delivery) (Delivery, error)
+	FindDeliveries(ctx context.Context, endpointID int, limit int) ([]Delivery, error)
+}
+
+type repository struct {
+	db      *gorm.DB
+	timeout time.Duration
+}
+
+func NewRepository(db *gorm.DB, timeout time.Duration) *repository {
+	return &repository{db, timeout}
+}
+
+func (r *repository) withTimeout(ctx context.Context) (*gorm.DB, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	return r.db.WithContext(ctx), cancel
+}
+
+func (r *repository) Save(ctx context.Context, endpoint Endpoint) (Endpoint, error) {
+	db, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	err := db.Create(&endpoint).Error
+	if err != nil {
+		return endpoint, err
+	}
+
+	return endpoint, nil
+}
+
+func (r *repository) FindByID(ctx context.Context, ID int) (Endpoint, error) {
+	db, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	var endpoint Endpoint
+
+	err := db.Where("id = ?", ID).Find(&endpoint).Error
+	if err != nil {
+		return endpoint, err
+	}
+
+	return endpoint, nil
+}
+
+func (r *repository) FindByUserID(ctx context.Context, userID int) ([]Endpoint, error) {
+	db, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	var endpoints []Endpoint
+
+	err := db.Where("user_id = ?", userID).Order("created_at desc").Find(&endpoints).Error
+	if err != nil {
+		return endpoints, err
+	}
+
+	return endpoints, nil
+}
+
+func (r *repository) Delete(ctx context.Context, ID int, userID int) (bool, error) {
+	db, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	result := db.Where("id = ? AND user_id = ?", ID, userID).Delete(&Endpoint{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+func (r *repository) UpdateSecret(ctx context.Context, ID int, secret string) error {
+	db, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	return db.Model(&Endpoint{}).Where("id = ?", ID).Update("secret", secret).Error
+}
+
+func (r *repository) SaveDelivery(ctx context.Context, delivery Delivery) (Delivery, error) {
+	db, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	err := db.Create(&delivery).Error
+	if err != nil {
+		return delivery, err
+	}
+
+	return delivery, nil
+}
+
+func (r *repository) FindDeliveries(ctx context.Context, endpointID int, limit int) ([]Delivery, error) {
+	db, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	var deliveries []Delivery
+
+	err := db.Where("endpoint_id = ?", endpointID).Order("created_at desc").Limit(limit).Find(&deliveries).Error
+	if err != nil {
+		return deliveries, err
+	}
+
+	return deliveries, nil
+}
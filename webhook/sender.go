@@ -0,0 +1,158 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// response body yang disimpan di log pengiriman dipotong supaya tabel tidak membengkak
+const maxResponseBody = 1024
+
+var errPrivateAddress = errors.New("webhook: destination address is not allowed")
+
+// SenderConfig mengatur HTTP client untuk pengiriman webhook
+type SenderConfig struct {
+	Timeout time.Duration
+	// AllowPrivate mengizinkan alamat private / loopback, hanya untuk development
+	AllowPrivate bool
+}
+
+type Sender struct {
+	client *http.Client
+}
+
+// Result adalah hasil satu percobaan pengiriman
+type Result struct {
+	StatusCode   int
+	ResponseBody string
+	Duration     time.Duration
+	Err          error
+}
+
+func NewSender(config SenderConfig) *Sender {
+	dialer := &net.Dialer{Timeout: config.Timeout}
+
+	// URL webhook diisi user, alamat IP dicek setelah DNS di-resolve supaya
+	// server tidak bisa dipakai untuk mengakses jaringan internal (SSRF)
+	if !config.AllowPrivate {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+
+			ip := net.ParseIP(host)
+			if ip == nil || !isPublicIP(ip) {
+				return errPrivateAddress
+			}
+
+			return nil
+		}
+	}
+
+	transport := &http.Transport{
+		Proxy:                 nil,
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   config.Timeout,
+		ResponseHeaderTimeout: config.Timeout,
+		MaxIdleConnsPerHost:   2,
+		IdleConnTimeout:       time.Minute,
+	}
+
+	client := &http.Client{
+		Timeout:   config.Timeout,
+		Transport: transport,
+		// redirect tidak diikuti, endpoint harus membalas langsung
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	return &Sender{client}
+}
+
+// Send menandatangani dan mengirim event ke endpoint. Header
+// X-Webhook-Signature berisi "t=<unix>,v1=<hex>" dengan v1 adalah
+// HMAC-SHA256(secret, "<unix>.<body>").
+func (s *Sender) Send(ctx context.Context, endpoint Endpoint, message Message) Result {
+	result := Result{}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"id":         message.EventID,
+		"type":       message.EventType,
+		"created_at": message.CreatedAt,
+		"data":       message.Data,
+	})
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	timestamp := time.Now().Unix()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "crowdfunding-webhook/1.0")
+	request.Header.Set("X-Webhook-Event", message.EventType)
+	request.Header.Set("X-Webhook-Delivery", message.EventID)
+	request.Header.Set("X-Webhook-Signature", fmt.Sprintf("t=%d,v1=%s", timestamp, Sign(endpoint.Secret, timestamp, body)))
+
+	start := time.Now()
+	response, err := s.client.Do(request)
+	result.Duration = time.Since(start)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	defer response.Body.Close()
+
+	responseBody, _ := io.ReadAll(io.LimitReader(response.Body, maxResponseBody))
+
+	result.StatusCode = response.StatusCode
+	result.ResponseBody = sanitizeBody(responseBody)
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		result.Err = fmt.Errorf("webhook: endpoint responded with status %d", response.StatusCode)
+	}
+
+	return result
+}
+
+// Sign menghitung tanda tangan yang juga harus dihitung penerima untuk memverifikasi payload
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func isPublicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast())
+}
+
+// sanitizeBody membuat body bisa disimpan di kolom text postgres: potongan
+// 1024 byte bisa memutus karakter multi-byte dan postgres menolak NUL
+func sanitizeBody(body []byte) string {
+	return strings.ReplaceAll(strings.ToValidUTF8(string(body), ""), "\x00", "")
+}
@@ -0,0 +1,28 @@
+package webhook
+
+import "testing"
+
+// nilai expected dihitung terpisah dengan
+// printf '<timestamp>.<body>' | openssl dgst -sha256 -hmac '<secret>'
+func TestSign(t *testing.T) {
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp int64
+		body      string
+		want      string
+	}{
+		{"payload", "whsec_test", 1700000000, `{"id":"evt_1"}`, "c89214b5b5da833daed6f0b8c5bb6bd58cea9022bd80ccc78230f3942d632925"},
+		{"timestamp ikut ditandatangani", "whsec_test", 1700000001, `{"id":"evt_1"}`, "a6b8e4670849f25456dbcceec15faae9edf44ea78d5607a06ebcb96ce7583658"},
+		{"body kosong", "secret", 0, "", "3445798a051818ef95def46c2eb62b43d377ce6e3c29b4d0aec3da0e59577f79"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Sign(tt.secret, tt.timestamp, []byte(tt.body))
+			if got != tt.want {
+				t.Errorf("Sign() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,304 @@
+package webhook
+
+import (
+	"auth-gorm-echo/apperror"
+	"auth-gorm-echo/encryption"
+	"auth-gorm-echo/jobs"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// jumlah log pengiriman yang ditampilkan per endpoint
+const deliveryLogLimit = 50
+
+var ErrEndpointNotFound = apperror.NotFound("webhook.not_found", "Webhook endpoint not found")
+
+// Message adalah payload job webhook.deliver, satu job untuk satu endpoint
+type Message struct {
+	EndpointID int             `json:"endpoint_id"`
+	EventID    string          `json:"event_id"`
+	EventType  string          `json:"event_type"`
+	CreatedAt  time.Time       `json:"created_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+var DeliverJob = jobs.NewType[Message]("webhook.deliver")
+
+type Service interface {
+	CreateEndpoint(ctx context.Context, input CreateEndpointInput) (Endpoint, error)
+	GetEndpoints(ctx context.Context, userID int) ([]Endpoint, error)
+	DeleteEndpoint(ctx context.Context, input EndpointInput) error
+	GetDeliveries(ctx context.Context, input EndpointInput) ([]Delivery, error)
+	SendTestEvent(ctx context.Context, input EndpointInput) error
+	Dispatch(ctx context.Context, userID int, eventType string, eventID string, data interface{}) error
+	Deliver(ctx context.Context, message Message) error
+}
+
+// SecretCipher mengenkripsi secret endpoint sebelum disimpan ke database
+type SecretCipher interface {
+	Encrypt(plaintext string) (string, error)
+	Decrypt(value string) (string, error)
+}
+
+type service struct {
+	repository Repository
+	queue      *jobs.Queue
+	sender     *Sender
+	cipher     SecretCipher
+	logger     *slog.Logger
+}
+
+func NewService(repository Repository, queue *jobs.Queue, sender *Sender, cipher SecretCipher, logger *slog.Logger) *service {
+	return &service{repository, queue, sender, cipher, logger}
+}
+
+// CreateEndpoint membuat secret baru, secret hanya ditampilkan sekali saat endpoint dibuat
+func (s *service) CreateEndpoint(ctx context.Context, input CreateEndpointInput) (Endpoint, error) {
+	endpoint := Endpoint{}
+
+	secret, err := randomSecret()
+	if err != nil {
+		return endpoint, err
+	}
+
+	encryptedSecret, err := s.cipher.Encrypt(secret)
+	if err != nil {
+		return endpoint, err
+	}
+
+	endpoint.UserID = input.User.ID
+	endpoint.URL = input.URL
+	endpoint.Secret = encryptedSecret
+	endpoint.Events = strings.Join(uniqueEvents(input.Events), ",")
+
+	newEndpoint, err := s.repository.Save(ctx, endpoint)
+	if err != nil {
+		return newEndpoint, err
+	}
+
+	// dikembalikan plain untuk ditampilkan sekali ke user
+	newEndpoint.Secret = secret
+
+	return newEndpoint, nil
+}
+
+func (s *service) GetEndpoints(ctx context.Context, userID int) ([]Endpoint, error) {
+	endpoints, err := s.repository.FindByUserID(ctx, userID)
+	if err != nil {
+		return endpoints, err
+	}
+
+	return endpoints, nil
+}
+
+func (s *service) DeleteEndpoint(ctx context.Context, input EndpointInput) error {
+	deleted, err := s.repository.Delete(ctx, input.ID, input.User.ID)
+	if err != nil {
+		return err
+	}
+
+	if !deleted {
+		return ErrEndpointNotFound
+	}
+
+	return nil
+}
+
+func (s *service) GetDeliveries(ctx context.Context, input EndpointInput) ([]Delivery, error) {
+	endpoint, err := s.findOwned(ctx, input)
+	if err != nil {
+		return []Delivery{}, err
+	}
+
+	deliveries, err := s.repository.FindDeliveries(ctx, endpoint.ID, deliveryLogLimit)
+	if err != nil {
+		return deliveries, err
+	}
+
+	return deliveries, nil
+}
+
+// SendTestEvent mengirim event webhook.test ke satu endpoint tanpa melihat
+// event yang di-subscribe, hasilnya bisa dilihat di log pengiriman
+func (s *service) SendTestEvent(ctx context.Context, input EndpointInput) error {
+	endpoint, err := s.findOwned(ctx, input)
+	if err != nil {
+		return err
+	}
+
+	eventID, err := randomEventID()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(map[string]interface{}{"endpoint_id": endpoint.ID})
+	if err != nil {
+		return err
+	}
+
+	// test event tidak di-retry supaya hasilnya langsung terlihat di log
+	_, err = DeliverJob.Enqueue(ctx, s.queue, Message{
+		EndpointID: endpoint.ID,
+		EventID:    eventID,
+		EventType:  EventTest,
+		CreatedAt:  time.Now(),
+		Data:       data,
+	}, jobs.MaxAttempts(1))
+
+	return err
+}
+
+// Dispatch memasukkan satu job pengiriman untuk setiap endpoint milik user
+// yang subscribe ke eventType. eventID harus stabil (misalnya ID outbox)
+// supaya event yang diproses ulang tidak terkirim dua kali.
+func (s *service) Dispatch(ctx context.Context, userID int, eventType string, eventID string, data interface{}) error {
+	endpoints, err := s.repository.FindByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	for _, endpoint := range endpoints {
+		if !endpoint.IsSubscribed(eventType) {
+			continue
+		}
+
+		message := Message{
+			EndpointID: endpoint.ID,
+			EventID:    eventID,
+			EventType:  eventType,
+			CreatedAt:  now,
+			Data:       payload,
+		}
+
+		_, err = DeliverJob.Enqueue(ctx, s.queue, message, jobs.ID(fmt.Sprintf("webhook:%s:%d", eventID, endpoint.ID)))
+		if err != nil && !errors.Is(err, jobs.ErrDuplicateJob) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Deliver dipanggil worker untuk job webhook.deliver. Setiap percobaan dicatat
+// di log pengiriman, response selain 2xx dikembalikan sebagai error supaya job
+// di-retry dengan backoff eksponensial.
+func (s *service) Deliver(ctx context.Context, message Message) error {
+	endpoint, err := s.repository.FindByID(ctx, message.EndpointID)
+	if err != nil {
+		return err
+	}
+
+	// endpoint sudah dihapus, tidak perlu dikirim lagi
+	if endpoint.ID == 0 {
+		return nil
+	}
+
+	secret, err := s.cipher.Decrypt(endpoint.Secret)
+	if err != nil {
+		return err
+	}
+
+	// secret yang disimpan sebelum enkripsi diterapkan dienkripsi saat dipakai
+	if !encryption.IsEncrypted(endpoint.Secret) {
+		s.encryptLegacySecret(ctx, endpoint.ID, secret)
+	}
+
+	endpoint.Secret = secret
+
+	result := s.sender.Send(ctx, endpoint, message)
+
+	delivery := Delivery{
+		EndpointID:   endpoint.ID,
+		EventID:      message.EventID,
+		EventType:    message.EventType,
+		Attempt:      jobs.Attempt(ctx),
+		StatusCode:   result.StatusCode,
+		ResponseBody: result.ResponseBody,
+		DurationMs:   int(result.Duration.Milliseconds()),
+	}
+
+	if result.Err != nil {
+		delivery.Error = result.Err.Error()
+	}
+
+	// webhook yang sudah terkirim tidak dikirim ulang hanya karena log-nya gagal disimpan
+	_, err = s.repository.SaveDelivery(ctx, delivery)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to save webhook delivery", "endpoint_id", endpoint.ID, "event_id", message.EventID, "error", err)
+	}
+
+	return result.Err
+}
+
+func (s *service) encryptLegacySecret(ctx context.Context, endpointID int, secret string) {
+	encrypted, err := s.cipher.Encrypt(secret)
+	if err == nil {
+		err = s.repository.UpdateSecret(ctx, endpointID, encrypted)
+	}
+
+	if err != nil {
+		s.logger.WarnContext(ctx, "failed to encrypt legacy webhook secret", "endpoint_id", endpointID, "error", err)
+	}
+}
+
+func (s *service) findOwned(ctx context.Context, input EndpointInput) (Endpoint, error) {
+	endpoint, err := s.repository.FindByID(ctx, input.ID)
+	if err != nil {
+		return endpoint, err
+	}
+
+	// endpoint milik user lain diperlakukan sama dengan tidak ada
+	if endpoint.ID == 0 || endpoint.UserID != input.User.ID {
+		return Endpoint{}, ErrEndpointNotFound
+	}
+
+	return endpoint, nil
+}
+
+func randomSecret() (string, error) {
+	random := make([]byte, 24)
+	_, err := rand.Read(random)
+	if err != nil {
+		return "", err
+	}
+
+	return "whsec_" + hex.EncodeToString(random), nil
+}
+
+func randomEventID() (string, error) {
+	random := make([]byte, 12)
+	_, err := rand.Read(random)
+	if err != nil {
+		return "", err
+	}
+
+	return "evt_test_" + hex.EncodeToString(random), nil
+}
+
+func uniqueEvents(events []string) []string {
+	seen := map[string]bool{}
+	result := []string{}
+
+	for _, event := range events {
+		if !seen[event] {
+			seen[event] = true
+			result = append(result, event)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"auth-gorm-echo/campaign"
+	"auth-gorm-echo/config"
+	"auth-gorm-echo/jobs"
+	"auth-gorm-echo/mailer"
+	"auth-gorm-echo/outbox"
+	"auth-gorm-echo/pledge"
+	"auth-gorm-echo/webhook"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// closeExpiredCampaignsJob menggantikan cron `campaign close-expired`
+var closeExpiredCampaignsJob = jobs.NewType[struct{}]("campaign.close_expired")
+
+func newWorker(app *app) *jobs.Worker {
+	worker := jobs.NewWorker(app.queue, jobs.WorkerConfig{
+		Concurrency: config.GetenvInt("WORKER_CONCURRENCY", 4),
+		PollTimeout: time.Second * 2,
+		BaseBackoff: config.GetenvDuration("JOB_BACKOFF_BASE", time.Second*10),
+		MaxBackoff:  config.GetenvDuration("JOB_BACKOFF_MAX", time.Hour),
+	}, app.logger)
+
+	jobs.Handle(worker, mailer.SendEmailJob, mailer.SendEmailHandler(mailer.NewMailer()))
+	jobs.Handle(worker, closeExpiredCampaignsJob, func(ctx context.Context, _ struct{}) error {
+		_, err := app.campaignService.CloseExpiredCampaigns(ctx)
+		return err
+	})
+	jobs.Handle(worker, webhook.DeliverJob, app.webhookService.Deliver)
+	jobs.Handle(worker, outbox.JobType(campaign.EventCampaignCreated), notifyCampaignOwner(app, func(payload campaign.CampaignEvent) (string, string) {
+		return "Your campaign is live", fmt.Sprintf("Your campaign \"%s\" has been published and can now receive backers.", payload.Name)
+	}))
+	jobs.Handle(worker, outbox.JobType(campaign.EventCampaignClosed), notifyCampaignOwner(app, func(payload campaign.CampaignEvent) (string, string) {
+		return "Your campaign has closed", fmt.Sprintf("Your campaign \"%s\" has closed with %d of %d raised from %d backers.",
+			payload.Name, payload.CurrentAmount, payload.GoalAmount, payload.BackerCount)
+	}))
+
+	return worker
+}
+
+// notifyCampaignOwner membuat handler event campaign yang mengirim email ke
+// pemiliknya. ID event dipakai sebagai ID job email supaya event yang
+// dijalankan ulang tidak mengirim email dua kali selama job email masih antri.
+func notifyCampaignOwner(app *app, message func(payload campaign.CampaignEvent) (subject string, body string)) func(ctx context.Context, event outbox.Event) error {
+	return func(ctx context.Context, event outbox.Event) error {
+		var payload campaign.CampaignEvent
+
+		err := event.Decode(&payload)
+		if err != nil {
+			return err
+		}
+
+		owner, err := app.userService.GetUserByID(ctx, payload.UserID)
+		if err != nil {
+			return err
+		}
+
+		subject, body := message(payload)
+		body = fmt.Sprintf("Hi %s,\n\n%s", owner.Name, body)
+
+		_, err = mailer.SendEmailJob.Enqueue(ctx, app.queue, mailer.Email{To: owner.Email, Subject: subject, Body: body}, jobs.ID(fmt.Sprintf("%s-%d", event.Type, event.ID)))
+		if err != nil && !errors.Is(err, jobs.ErrDuplicateJob) {
+			return err
+		}
+
+		return nil
+	}
+}
+
+// newRelay mengatur ke mana domain event di outbox dikirim
+func newRelay(app *app) *outbox.Relay {
+	relay := outbox.NewRelay(app.db, app.queue, outbox.RelayConfig{
+		BatchSize:    config.GetenvInt("OUTBOX_BATCH_SIZE", 100),
+		PollInterval: config.GetenvDuration("OUTBOX_POLL_INTERVAL", time.Second),
+		BaseBackoff:  time.Second * 5,
+		MaxBackoff:   time.Minute * 10,
+		Retention:    config.GetenvDuration("OUTBOX_RETENTION", time.Hour*24*7),
+		ClaimTimeout: config.GetenvDuration("OUTBOX_CLAIM_TIMEOUT", time.Minute),
+	}, app.logger)
+
+	// webhook creator, dikirim ke pemilik campaign
+	campaignOwner := func(payload campaign.CampaignEvent) int { return payload.UserID }
+	pledgeCampaignOwner := func(payload pledge.PledgeEvent) int { return payload.Campaign.UserID }
+
+	relay.Subscribe(pledge.EventPledgePaid, dispatchWebhook(app, webhook.EventPledgePaid, pledgeCampaignOwner))
+	relay.Subscribe(pledge.EventPledgeRefunded, dispatchWebhook(app, webhook.EventPledgeRefunded, pledgeCampaignOwner))
+	relay.Subscribe(campaign.EventCampaignFunded, dispatchWebhook(app, webhook.EventCampaignFunded, campaignOwner))
+	relay.Subscribe(campaign.EventCampaignClosed, dispatchWebhook(app, webhook.EventCampaignClosed, campaignOwner))
+
+	// progress pendanaan ke client stream di semua instance
+	relay.Subscribe(campaign.EventCampaignBacked, app.progressHub.PublishBacking)
+	relay.Subscribe(campaign.EventCampaignBackingRefunded, app.progressHub.PublishBacking)
+
+	// email ke pemilik campaign dikirim lewat antrian job, lihat newWorker
+	relay.Forward(campaign.EventCampaignCreated)
+	relay.Forward(campaign.EventCampaignClosed)
+
+	return relay
+}
+
+// dispatchWebhook mengirim event ke webhook milik user yang dikembalikan
+// owner. ID event outbox dipakai sebagai ID event webhook supaya event yang
+// dipublish ulang tidak terkirim dua kali.
+func dispatchWebhook[T any](app *app, eventType string, owner func(payload T) int) outbox.Subscriber {
+	return func(ctx context.Context, event outbox.Event) error {
+		var payload T
+
+		err := event.Decode(&payload)
+		if err != nil {
+			return err
+		}
+
+		return app.webhookService.Dispatch(ctx, owner(payload), eventType, fmt.Sprintf("evt_%d", event.ID), payload)
+	}
+}
+
+// startWorker menjalankan worker, relay outbox dan penjadwal job berkala di
+// background, fungsi yang dikembalikan menunggu semuanya berhenti setelah ctx
+// dibatalkan
+func startWorker(ctx context.Context, app *app) func() {
+	var wg sync.WaitGroup
+
+	worker := newWorker(app)
+	relay := newRelay(app)
+
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		worker.Run(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+		relay.Run(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+		schedulePeriodicJobs(ctx, app)
+	}()
+
+	app.logger.Info("job worker started")
+
+	return wg.Wait
+}
+
+// schedulePeriodicJobs memasukkan job berkala. ID job diambil dari slot waktu
+// sehingga kalau ada beberapa worker, job hanya masuk antrian sekali per slot.
+func schedulePeriodicJobs(ctx context.Context, app *app) {
+	interval := config.GetenvDuration("CLOSE_EXPIRED_INTERVAL", time.Minute*5)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		slot := time.Now().Truncate(interval).Unix()
+
+		_, err := closeExpiredCampaignsJob.Enqueue(ctx, app.queue, struct{}{}, jobs.ID(fmt.Sprintf("%s:%d", closeExpiredCampaignsJob.Name, slot)))
+		if err != nil && !errors.Is(err, jobs.ErrDuplicateJob) && ctx.Err() == nil {
+			app.logger.Error("failed to schedule periodic job", "job_type", closeExpiredCampaignsJob.Name, "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runWorker: worker, menjalankan job tanpa HTTP server
+func runWorker(args []string) {
+	app := newApp()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	wait := startWorker(ctx, app)
+
+	<-ctx.Done()
+	app.logger.Info("shutting down worker, waiting for running jobs")
+	wait()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), config.GetenvDuration("SHUTDOWN_TIMEOUT", time.Second*20))
+	defer cancel()
+
+	app.close(shutdownCtx)
+}